@@ -1,9 +1,11 @@
 package openai
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
 	"net/url"
-	"strconv"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
 )
 
 const AssistantsEndpointPath = "/assistants/"
@@ -35,8 +37,33 @@ type Assistant struct {
 	MetaData      map[string]string       `json:"metadata,omitempty"`
 	Temperature   float64                 `json:"temperature,omitempty"`
 	TopP          float64                 `json:"top_p,omitempty"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the Assistant was decoded from.
+func (a *Assistant) RawJSON() json.RawMessage {
+	return a.rawJSON
+}
+
+func (a *Assistant) UnmarshalJSON(data []byte) error {
+	type alias Assistant
+	extra, err := unmarshalWithExtra(data, (*alias)(a))
+	a.ExtraFields = extra
+	a.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (a Assistant) MarshalJSON() ([]byte, error) {
+	type alias Assistant
+	return marshalWithExtra(alias(a), a.ExtraFields)
 }
 
+func (a *Assistant) objectID() string { return a.Id }
+
 type Assistants struct {
 	Object  string      `json:"object"`
 	Data    []Assistant `json:"data"`
@@ -68,6 +95,14 @@ type AssistantRequest struct {
 
 	Temperature float64 `json:"temperature,omitempty"`
 	TopP        float64 `json:"top_p,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (a AssistantRequest) MarshalJSON() ([]byte, error) {
+	type alias AssistantRequest
+	return marshalWithExtra(alias(a), a.ExtraFields)
 }
 
 type AssistantFile struct {
@@ -75,8 +110,33 @@ type AssistantFile struct {
 	Object      string `json:"object"` // The object type, which is always assistant.file.
 	CreatedAt   int64  `json:"created_at"`
 	AssistantId string `json:"assistant_id"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the AssistantFile was decoded from.
+func (a *AssistantFile) RawJSON() json.RawMessage {
+	return a.rawJSON
 }
 
+func (a *AssistantFile) UnmarshalJSON(data []byte) error {
+	type alias AssistantFile
+	extra, err := unmarshalWithExtra(data, (*alias)(a))
+	a.ExtraFields = extra
+	a.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (a AssistantFile) MarshalJSON() ([]byte, error) {
+	type alias AssistantFile
+	return marshalWithExtra(alias(a), a.ExtraFields)
+}
+
+func (f *AssistantFile) objectID() string { return f.Id }
+
 type AssistantFiles struct {
 	Object  string          `json:"object"`
 	Data    []AssistantFile `json:"data"`
@@ -86,6 +146,14 @@ type AssistantFiles struct {
 type AssistantFileRequest struct {
 	// A File ID (with purpose="assistants") that the assistant should use. Useful for tools like retrieval and code_interpreter that can access files.
 	FileId *string `json:"file_id"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (r AssistantFileRequest) MarshalJSON() ([]byte, error) {
+	type alias AssistantFileRequest
+	return marshalWithExtra(alias(r), r.ExtraFields)
 }
 
 type AssistantTool struct {
@@ -112,35 +180,55 @@ type AssistantToolResources struct {
 
 // Create an assistant with a model and instructions.
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/assistants/createAssistant
-func (e *AssistantsEndpoint) CreateAssistant(req *AssistantRequest) (*Assistant, error) {
+func (e *AssistantsEndpoint) CreateAssistant(req *AssistantRequest, opts ...option.RequestOption) (*Assistant, error) {
+	return e.CreateAssistantWithContext(context.Background(), req, opts...)
+}
+
+// CreateAssistantWithContext is like CreateAssistant but uses ctx for the request.
+func (e *AssistantsEndpoint) CreateAssistantWithContext(ctx context.Context, req *AssistantRequest, opts ...option.RequestOption) (*Assistant, error) {
 	var assistant Assistant
-	err := e.do(e, "POST", "", req, nil, &assistant)
+	err := e.do(ctx, e, "POST", "", req, nil, &assistant, opts...)
 	return &assistant, err
 }
 
 // Retrieves an assistant.
-func (e *AssistantsEndpoint) RetrieveAssistant(assistantId string) (*Assistant, error) {
+func (e *AssistantsEndpoint) RetrieveAssistant(assistantId string, opts ...option.RequestOption) (*Assistant, error) {
+	return e.RetrieveAssistantWithContext(context.Background(), assistantId, opts...)
+}
+
+// RetrieveAssistantWithContext is like RetrieveAssistant but uses ctx for the request.
+func (e *AssistantsEndpoint) RetrieveAssistantWithContext(ctx context.Context, assistantId string, opts ...option.RequestOption) (*Assistant, error) {
 	var assistant Assistant
-	err := e.do(e, "GET", assistantId, nil, nil, &assistant)
+	err := e.do(ctx, e, "GET", assistantId, nil, nil, &assistant, opts...)
 	return &assistant, err
 }
 
 // Modifies an assistant.
-func (e *AssistantsEndpoint) ModifyAssistant(assistantId string, req *AssistantRequest) (*Assistant, error) {
+func (e *AssistantsEndpoint) ModifyAssistant(assistantId string, req *AssistantRequest, opts ...option.RequestOption) (*Assistant, error) {
+	return e.ModifyAssistantWithContext(context.Background(), assistantId, req, opts...)
+}
+
+// ModifyAssistantWithContext is like ModifyAssistant but uses ctx for the request.
+func (e *AssistantsEndpoint) ModifyAssistantWithContext(ctx context.Context, assistantId string, req *AssistantRequest, opts ...option.RequestOption) (*Assistant, error) {
 	var assistant Assistant
-	err := e.do(e, "POST", assistantId, req, nil, &assistant)
+	err := e.do(ctx, e, "POST", assistantId, req, nil, &assistant, opts...)
 	return &assistant, err
 }
 
 // Deletes an assistant.
-func (e *AssistantsEndpoint) DeleteAssistant(assistantId string) (bool, error) {
+func (e *AssistantsEndpoint) DeleteAssistant(assistantId string, opts ...option.RequestOption) (bool, error) {
+	return e.DeleteAssistantWithContext(context.Background(), assistantId, opts...)
+}
+
+// DeleteAssistantWithContext is like DeleteAssistant but uses ctx for the request.
+func (e *AssistantsEndpoint) DeleteAssistantWithContext(ctx context.Context, assistantId string, opts ...option.RequestOption) (bool, error) {
 	type DeleteResponse struct {
 		Id      string `json:"id"`
 		Object  string `json:"object"`
 		Deleted bool   `json:"deleted"`
 	}
 	var resp DeleteResponse
-	err := e.do(e, "DELETE", url.QueryEscape(assistantId), nil, nil, &resp)
+	err := e.do(ctx, e, "DELETE", url.QueryEscape(assistantId), nil, nil, &resp, opts...)
 	if err != nil {
 		return false, err
 	}
@@ -148,61 +236,98 @@ func (e *AssistantsEndpoint) DeleteAssistant(assistantId string) (bool, error) {
 }
 
 // Returns a list of assistants.
-func (e *AssistantsEndpoint) ListAssistants(after *string, limit *int) ([]Assistant, error) {
-	v := url.Values{}
-	if after != nil {
-		v.Add("after", *after)
-	}
-	if limit != nil {
-		v.Add("limit", strconv.Itoa(*limit))
-	}
-	var assistants Assistants
-	err := e.do(e, "GET", "", nil, v, &assistants)
-	// TODO: This needs to move somewhere central
-	if err == nil && assistants.Object != "list" {
-		err = fmt.Errorf("expected 'list' object type, got %s", assistants.Object)
-	}
-	return assistants.Data, err
+func (e *AssistantsEndpoint) ListAssistants(after *string, limit *int, opts ...option.RequestOption) ([]Assistant, error) {
+	return e.ListAssistantsWithContext(context.Background(), after, limit, opts...)
+}
+
+// ListAssistantsWithContext is like ListAssistants but uses ctx for the request.
+func (e *AssistantsEndpoint) ListAssistantsWithContext(ctx context.Context, after *string, limit *int, opts ...option.RequestOption) ([]Assistant, error) {
+	page, err := e.ListAssistantsPage(ctx, listParams(after, limit), opts...)
+	return page.Data, err
+}
+
+// ListAssistantsPage returns a page of assistants.
+func (e *AssistantsEndpoint) ListAssistantsPage(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[Assistant], error) {
+	return listPage[Assistant](ctx, e.Client, e, "", params, opts...)
+}
+
+// ListAssistantsAutoPaging iterates over every assistant, starting from params.
+func (e *AssistantsEndpoint) ListAssistantsAutoPaging(ctx context.Context, params *ListParams, opts ...option.RequestOption) *Iter[Assistant] {
+	return newIter(ctx, params, e.ListAssistantsPage, opts...)
 }
 
 // Creates an assistant file.
-func (e *AssistantsEndpoint) CreateAssistantFile(assistantId string, fileId string) (*AssistantFile, error) {
+func (e *AssistantsEndpoint) CreateAssistantFile(assistantId string, fileId string, opts ...option.RequestOption) (*AssistantFile, error) {
+	return e.CreateAssistantFileWithContext(context.Background(), assistantId, fileId, opts...)
+}
+
+// CreateAssistantFileWithContext is like CreateAssistantFile but uses ctx for the request.
+func (e *AssistantsEndpoint) CreateAssistantFileWithContext(ctx context.Context, assistantId string, fileId string, opts ...option.RequestOption) (*AssistantFile, error) {
 	req := AssistantFileRequest{
 		FileId: &fileId,
 	}
 	var file AssistantFile
-	err := e.do(e, "POST", assistantId, req, nil, &file)
+	err := e.do(ctx, e, "POST", assistantId+"/files", req, nil, &file, opts...)
 	return &file, err
 }
 
 // Retrieves an assistant file.
-func (e *AssistantsEndpoint) RetrieveAssistantFile(assistantId string, fileId string) (*AssistantFile, error) {
+func (e *AssistantsEndpoint) RetrieveAssistantFile(assistantId string, fileId string, opts ...option.RequestOption) (*AssistantFile, error) {
+	return e.RetrieveAssistantFileWithContext(context.Background(), assistantId, fileId, opts...)
+}
+
+// RetrieveAssistantFileWithContext is like RetrieveAssistantFile but uses ctx for the request.
+func (e *AssistantsEndpoint) RetrieveAssistantFileWithContext(ctx context.Context, assistantId string, fileId string, opts ...option.RequestOption) (*AssistantFile, error) {
 	var file AssistantFile
-	err := e.do(e, "GET", assistantId+"/files/"+fileId, nil, nil, &file)
+	err := e.do(ctx, e, "GET", assistantId+"/files/"+fileId, nil, nil, &file, opts...)
 	return &file, err
 }
 
 // Deletes an assistant file.
-func (e *AssistantsEndpoint) DeleteAssistantFile(assistantId string, fileId string) (bool, error) {
-	var deleted bool
-	err := e.do(e, "DELETE", assistantId+"/files/"+fileId, nil, nil, &deleted)
-	return deleted, err
+func (e *AssistantsEndpoint) DeleteAssistantFile(assistantId string, fileId string, opts ...option.RequestOption) (bool, error) {
+	return e.DeleteAssistantFileWithContext(context.Background(), assistantId, fileId, opts...)
 }
 
-// Returns a list of assistant files.
-func (e *AssistantsEndpoint) ListAssistantFiles(after *string, limit *int) ([]AssistantFile, error) {
-	v := url.Values{}
-	if after != nil {
-		v.Add("after", *after)
-	}
-	if limit != nil {
-		v.Add("limit", strconv.Itoa(*limit))
+// DeleteAssistantFileWithContext is like DeleteAssistantFile but uses ctx for the request.
+func (e *AssistantsEndpoint) DeleteAssistantFileWithContext(ctx context.Context, assistantId string, fileId string, opts ...option.RequestOption) (bool, error) {
+	type DeleteResponse struct {
+		Id      string `json:"id"`
+		Object  string `json:"object"`
+		Deleted bool   `json:"deleted"`
 	}
-	var files AssistantFiles
-	err := e.do(e, "GET", "", nil, v, &files)
-	// TODO: This needs to move somewhere central
-	if err == nil && files.Object != "list" {
-		err = fmt.Errorf("expected 'list' object type, got %s", files.Object)
+	var resp DeleteResponse
+	err := e.do(ctx, e, "DELETE", assistantId+"/files/"+fileId, nil, nil, &resp, opts...)
+	if err != nil {
+		return false, err
 	}
-	return files.Data, err
+	return resp.Deleted, nil
+}
+
+// Returns a list of assistant files.
+//
+// Deprecated: this lists from the assistants collection rather than an assistant's files.
+// Use ListAssistantFilesPage or ListAssistantFilesAutoPaging.
+func (e *AssistantsEndpoint) ListAssistantFiles(after *string, limit *int, opts ...option.RequestOption) ([]AssistantFile, error) {
+	return e.ListAssistantFilesWithContext(context.Background(), after, limit, opts...)
+}
+
+// ListAssistantFilesWithContext is like ListAssistantFiles but uses ctx for the request.
+//
+// Deprecated: this lists from the assistants collection rather than an assistant's files.
+// Use ListAssistantFilesPage or ListAssistantFilesAutoPaging.
+func (e *AssistantsEndpoint) ListAssistantFilesWithContext(ctx context.Context, after *string, limit *int, opts ...option.RequestOption) ([]AssistantFile, error) {
+	page, err := listPage[AssistantFile](ctx, e.Client, e, "", listParams(after, limit), opts...)
+	return page.Data, err
+}
+
+// ListAssistantFilesPage returns a page of the files of an assistant.
+func (e *AssistantsEndpoint) ListAssistantFilesPage(ctx context.Context, assistantId string, params *ListParams, opts ...option.RequestOption) (*Page[AssistantFile], error) {
+	return listPage[AssistantFile](ctx, e.Client, e, assistantId+"/files", params, opts...)
+}
+
+// ListAssistantFilesAutoPaging iterates over every file of an assistant, starting from params.
+func (e *AssistantsEndpoint) ListAssistantFilesAutoPaging(ctx context.Context, assistantId string, params *ListParams, opts ...option.RequestOption) *Iter[AssistantFile] {
+	return newIter(ctx, params, func(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[AssistantFile], error) {
+		return e.ListAssistantFilesPage(ctx, assistantId, params, opts...)
+	}, opts...)
 }
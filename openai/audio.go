@@ -1,5 +1,14 @@
 package openai
 
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
 const AudioEndpointPath = "/audio/"
 
 // Audio Endpoint
@@ -12,11 +21,44 @@ type AudioEndpoint struct {
 
 // Audio Endpoint
 func (c *Client) Audio() *AudioEndpoint {
-	return &AudioEndpoint{newEndpoint(c, AudioEndpointPath)}
+	return &AudioEndpoint{newDeploymentEndpoint(c, AudioEndpointPath)}
 }
 
 type AudioResponse struct {
 	Text string `json:"text"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the AudioResponse was decoded from.
+func (a *AudioResponse) RawJSON() json.RawMessage {
+	return a.rawJSON
+}
+
+func (a *AudioResponse) UnmarshalJSON(data []byte) error {
+	type alias AudioResponse
+	extra, err := unmarshalWithExtra(data, (*alias)(a))
+	a.ExtraFields = extra
+	a.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (a AudioResponse) MarshalJSON() ([]byte, error) {
+	type alias AudioResponse
+	return marshalWithExtra(alias(a), a.ExtraFields)
+}
+
+// unmarshalRaw accepts both JSON and the plain text, srt and vtt response formats.
+func (r *AudioResponse) unmarshalRaw(contentType string, data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if strings.Contains(contentType, "json") || bytes.HasPrefix(trimmed, []byte("{")) {
+		return json.Unmarshal(data, r)
+	}
+	r.Text = string(data)
+	return nil
 }
 
 type AudioTranscriptionRequest struct {
@@ -34,6 +76,18 @@ type AudioTranscriptionRequest struct {
 	Temperature int `json:"temperature,omitempty"`
 	// The language of the input audio. Supplying the input language in ISO-639-1 format will improve accuracy and latency.
 	Language string `json:"language,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (r AudioTranscriptionRequest) MarshalJSON() ([]byte, error) {
+	type alias AudioTranscriptionRequest
+	return marshalWithExtra(alias(r), r.ExtraFields)
+}
+
+func (r *AudioTranscriptionRequest) requestModel() string {
+	return r.Model
 }
 
 type AudioTranslationRequest struct {
@@ -49,22 +103,44 @@ type AudioTranslationRequest struct {
 	// Defaults to 0
 	// The sampling temperature, between 0 and 1. Higher values like 0.8 will make the output more random, while lower values like 0.2 will make it more focused and deterministic. If set to 0, the model will use log probability to automatically increase the temperature until certain thresholds are hit.
 	Temperature int `json:"temperature,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (r AudioTranslationRequest) MarshalJSON() ([]byte, error) {
+	type alias AudioTranslationRequest
+	return marshalWithExtra(alias(r), r.ExtraFields)
+}
+
+func (r *AudioTranslationRequest) requestModel() string {
+	return r.Model
 }
 
 // Transcribes audio into the input language.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/audio/create
-func (e *AudioEndpoint) CreateTranscription(req *AudioTranscriptionRequest) (*AudioResponse, error) {
+func (e *AudioEndpoint) CreateTranscription(req *AudioTranscriptionRequest, opts ...option.RequestOption) (*AudioResponse, error) {
+	return e.CreateTranscriptionWithContext(context.Background(), req, opts...)
+}
+
+// CreateTranscriptionWithContext is like CreateTranscription but uses ctx for the request.
+func (e *AudioEndpoint) CreateTranscriptionWithContext(ctx context.Context, req *AudioTranscriptionRequest, opts ...option.RequestOption) (*AudioResponse, error) {
 	var resp AudioResponse
-	err := e.do(e, "POST", "transcriptions", req, nil, &resp)
+	err := e.do(ctx, e, "POST", "transcriptions", req, nil, &resp, opts...)
 	return &resp, err
 }
 
 // Translates audio into into English.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/audio/create
-func (e *AudioEndpoint) CreateTranslation(req *AudioTranslationRequest) (*AudioResponse, error) {
+func (e *AudioEndpoint) CreateTranslation(req *AudioTranslationRequest, opts ...option.RequestOption) (*AudioResponse, error) {
+	return e.CreateTranslationWithContext(context.Background(), req, opts...)
+}
+
+// CreateTranslationWithContext is like CreateTranslation but uses ctx for the request.
+func (e *AudioEndpoint) CreateTranslationWithContext(ctx context.Context, req *AudioTranslationRequest, opts ...option.RequestOption) (*AudioResponse, error) {
 	var resp AudioResponse
-	err := e.do(e, "POST", "translations", req, nil, &resp)
+	err := e.do(ctx, e, "POST", "translations", req, nil, &resp, opts...)
 	return &resp, err
 }
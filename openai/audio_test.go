@@ -57,3 +57,27 @@ func TestCreateAudioTranslation(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestCreateAudioTranscriptionTextFormat(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, "hello world")
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+
+	req := openai.AudioTranscriptionRequest{
+		Model:          "test",
+		ResponseFormat: "text",
+	}
+	resp, err := client.Audio().CreateTranscription(&req)
+	if err != nil {
+		t.Fatal(err, "CreateTranscription error")
+	}
+	if resp.Text != "hello world" {
+		t.Errorf("Text mismatch. Got %q. Expected %q", resp.Text, "hello world")
+	}
+}
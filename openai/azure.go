@@ -0,0 +1,95 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	azurePath              = "openai"
+	DefaultAzureAPIVersion = "2024-06-01"
+)
+
+// AzureConfig - configures a Client to call Azure OpenAI.
+//
+// Model requests (chat, completions, embeddings, edits, audio and images) are sent to
+// /openai/deployments/{deployment}/..., every other endpoint to /openai/...,
+// and all requests carry the api-version query parameter.
+// Requests are authenticated with the client API key in the api-key header,
+// or with a Microsoft Entra ID bearer token when TokenProvider is set.
+type AzureConfig struct {
+	// Azure OpenAI API version. Defaults to DefaultAzureAPIVersion.
+	APIVersion string
+	// Deployment names by model name. Models without an entry use the model name as the deployment name.
+	Deployments map[string]string
+	// Returns a Microsoft Entra ID access token for each request.
+	TokenProvider func(ctx context.Context) (string, error)
+}
+
+// WithAzure configures the client to call the Azure OpenAI resource at endpoint,
+// e.g. https://my-resource.openai.azure.com.
+func WithAzure(endpoint string, config AzureConfig) ClientOption {
+	return func(c *Client) error {
+		if err := WithBaseURL(endpoint)(c); err != nil {
+			return err
+		}
+		if config.APIVersion == "" {
+			config.APIVersion = DefaultAzureAPIVersion
+		}
+		c.Azure = &config
+		return nil
+	}
+}
+
+func (a *AzureConfig) deployment(model string) (string, error) {
+	if deployment, ok := a.Deployments[model]; ok {
+		return deployment, nil
+	}
+	if model == "" {
+		return "", errors.New("openai: azure requests require a model to select the deployment")
+	}
+	return model, nil
+}
+
+// authorize adds the Azure authentication header to req.
+func (a *AzureConfig) authorize(req *http.Request, apiKey string) error {
+	if a.TokenProvider != nil {
+		token, err := a.TokenProvider(req.Context())
+		if err != nil {
+			return fmt.Errorf("openai: azure token provider: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+	if len(apiKey) > 0 {
+		req.Header.Set("api-key", apiKey)
+	}
+	return nil
+}
+
+// ContentFilterResult - outcome of an Azure OpenAI content filter category.
+type ContentFilterResult struct {
+	Filtered bool   `json:"filtered"`
+	Severity string `json:"severity,omitempty"`
+	Detected *bool  `json:"detected,omitempty"`
+}
+
+// ContentFilterResults - Azure OpenAI content filtering results by category.
+type ContentFilterResults struct {
+	Hate                  *ContentFilterResult `json:"hate,omitempty"`
+	SelfHarm              *ContentFilterResult `json:"self_harm,omitempty"`
+	Sexual                *ContentFilterResult `json:"sexual,omitempty"`
+	Violence              *ContentFilterResult `json:"violence,omitempty"`
+	Profanity             *ContentFilterResult `json:"profanity,omitempty"`
+	Jailbreak             *ContentFilterResult `json:"jailbreak,omitempty"`
+	ProtectedMaterialText *ContentFilterResult `json:"protected_material_text,omitempty"`
+	ProtectedMaterialCode *ContentFilterResult `json:"protected_material_code,omitempty"`
+}
+
+// PromptFilterResult - Azure OpenAI content filtering results for a prompt.
+type PromptFilterResult struct {
+	PromptIndex          int                  `json:"prompt_index"`
+	ContentFilterResults ContentFilterResults `json:"content_filter_results"`
+}
@@ -0,0 +1,89 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+)
+
+func TestAzureChatCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/my-gpt4o/chat/completions" {
+			t.Errorf("Path mismatch. Got %s", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != "2024-06-01" {
+			t.Errorf("api-version mismatch. Got %s", v)
+		}
+		if r.Header.Get("api-key") != "azure-key" || r.Header.Get("Authorization") != "" {
+			t.Errorf("Unexpected auth headers: %v", r.Header)
+		}
+		fmt.Fprintln(w, `{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "Paris"},
+				"finish_reason": "stop",
+				"content_filter_results": {"hate": {"filtered": false, "severity": "safe"}}
+			}],
+			"prompt_filter_results": [{
+				"prompt_index": 0,
+				"content_filter_results": {"jailbreak": {"filtered": false, "detected": false}}
+			}]
+		}`)
+	}))
+	defer server.Close()
+
+	client, err := openai.NewClient(
+		openai.WithAPIKey("azure-key"),
+		openai.WithAzure(server.URL, openai.AzureConfig{
+			Deployments: map[string]string{"gpt-4o": "my-gpt4o"},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Chat().CreateChatCompletion(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletion error")
+	}
+	if resp.Choices[0].ContentFilterResults == nil || resp.Choices[0].ContentFilterResults.Hate.Severity != "safe" {
+		t.Errorf("Unexpected content filter results: %+v", resp.Choices[0].ContentFilterResults)
+	}
+	if len(resp.PromptFilterResults) != 1 || resp.PromptFilterResults[0].ContentFilterResults.Jailbreak == nil {
+		t.Errorf("Unexpected prompt filter results: %+v", resp.PromptFilterResults)
+	}
+}
+
+func TestAzureEntraIDAndNonDeploymentPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/files" {
+			t.Errorf("Path mismatch. Got %s", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != "2024-10-21" {
+			t.Errorf("api-version mismatch. Got %s", v)
+		}
+		if r.Header.Get("Authorization") != "Bearer entra-token" || r.Header.Get("api-key") != "" {
+			t.Errorf("Unexpected auth headers: %v", r.Header)
+		}
+		fmt.Fprintln(w, `{"object": "list", "data": []}`)
+	}))
+	defer server.Close()
+
+	client, err := openai.NewClient(openai.WithAzure(server.URL, openai.AzureConfig{
+		APIVersion: "2024-10-21",
+		TokenProvider: func(ctx context.Context) (string, error) {
+			return "entra-token", nil
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Files().ListFiles(); err != nil {
+		t.Error(err, "ListFiles error")
+	}
+}
@@ -0,0 +1,122 @@
+package openai
+
+import (
+	"context"
+	"sync"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
+const defaultBatchConcurrency = 4
+
+// BatchCall - an endpoint method run for each request of a batch,
+// e.g. client.Embeddings().CreateEmbeddingsWithContext.
+type BatchCall[Req any, Resp any] func(ctx context.Context, req Req, opts ...option.RequestOption) (Resp, error)
+
+// BatchOptions - configures RunBatch.
+type BatchOptions struct {
+	// Maximum number of requests in flight. Defaults to 4.
+	Concurrency int
+	// Cancel the remaining requests after the first failure.
+	StopOnError bool
+	// Called after each request completes. Calls are serialized.
+	OnProgress func(BatchProgress)
+	// Options applied to every request.
+	RequestOptions []option.RequestOption
+}
+
+// BatchProgress - the state of a batch after a request completes.
+type BatchProgress struct {
+	// Index of the request which completed.
+	Index int
+	// Error of the request which completed, if any.
+	Err error
+	// Number of requests completed so far, including failures.
+	Completed int
+	// Number of requests which failed so far.
+	Failed int
+	// Number of requests in the batch.
+	Total int
+}
+
+// BatchResult - the outcome of one request of a batch.
+type BatchResult[Resp any] struct {
+	Response Resp
+	Err      error
+}
+
+// RunBatch runs call for every request using a pool of workers and returns the results
+// in the order of reqs. Each request still goes through the client's retries, rate limiter,
+// cache and hooks, so a RateLimiter is the way to keep a large batch within the API limits.
+//
+// Failed requests are reported in their result's Err. The returned error is only set when
+// the batch stops early, because ctx is done or StopOnError is set; requests which were not
+// run then have that error as their Err.
+//
+//	results, err := openai.RunBatch(ctx, reqs, client.Embeddings().CreateEmbeddingsWithContext,
+//		openai.BatchOptions{Concurrency: 8})
+func RunBatch[Req any, Resp any](ctx context.Context, reqs []Req, call BatchCall[Req, Resp], opts BatchOptions) ([]BatchResult[Resp], error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	if concurrency > len(reqs) {
+		concurrency = len(reqs)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make([]BatchResult[Resp], len(reqs))
+	started := make([]bool, len(reqs))
+	indexes := make(chan int)
+	var mu sync.Mutex
+	progress := BatchProgress{Total: len(reqs)}
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				resp, err := call(ctx, reqs[i], opts.RequestOptions...)
+				results[i] = BatchResult[Resp]{Response: resp, Err: err}
+
+				mu.Lock()
+				progress.Index = i
+				progress.Err = err
+				progress.Completed++
+				if err != nil {
+					progress.Failed++
+					if opts.StopOnError {
+						cancel(err)
+					}
+				}
+				if opts.OnProgress != nil {
+					opts.OnProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for i := range reqs {
+		select {
+		case indexes <- i:
+			started[i] = true
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	err := context.Cause(ctx)
+	if err != nil {
+		for i := range results {
+			if !started[i] {
+				results[i].Err = err
+			}
+		}
+	}
+	return results, err
+}
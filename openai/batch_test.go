@@ -0,0 +1,127 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestRunBatch(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	fs.InjectFault(openai_test.Fault{Path: "/v1/embeddings", Latency: 5 * time.Millisecond})
+	client := fs.Client()
+
+	var reqs []*openai.EmbeddingsRequest
+	for i := 0; i < 20; i++ {
+		reqs = append(reqs, &openai.EmbeddingsRequest{Model: fmt.Sprintf("model-%d", i), Input: "test"})
+	}
+	var progressCalls, lastCompleted int
+	results, err := openai.RunBatch(context.Background(), reqs, client.Embeddings().CreateEmbeddingsWithContext, openai.BatchOptions{
+		Concurrency: 5,
+		OnProgress: func(p openai.BatchProgress) {
+			progressCalls++
+			lastCompleted = p.Completed
+		},
+	})
+	if err != nil {
+		t.Fatal(err, "RunBatch error")
+	}
+	for i, r := range results {
+		if r.Err != nil || r.Response.Model != reqs[i].Model {
+			t.Errorf("Result %d mismatch: %+v", i, r)
+		}
+	}
+	if progressCalls != 20 || lastCompleted != 20 {
+		t.Errorf("Unexpected progress: %d calls, %d completed", progressCalls, lastCompleted)
+	}
+}
+
+func TestRunBatchErrors(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	fs.ScriptFunc("/v1/embeddings", func(req map[string]any) (any, error) {
+		if req["input"] == "bad" {
+			return nil, &openai.APIError{Message: "bad input", HTTPStatusCode: http.StatusBadRequest}
+		}
+		return map[string]any{"object": "list", "model": req["model"]}, nil
+	})
+	client := fs.Client()
+
+	reqs := []*openai.EmbeddingsRequest{
+		{Model: "m", Input: "good"},
+		{Model: "m", Input: "bad"},
+		{Model: "m", Input: "good"},
+	}
+	results, err := openai.RunBatch(context.Background(), reqs, client.Embeddings().CreateEmbeddingsWithContext, openai.BatchOptions{})
+	if err != nil {
+		t.Fatal(err, "RunBatch error")
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("Unexpected errors: %v %v", results[0].Err, results[2].Err)
+	}
+	var apiErr *openai.APIError
+	if !errors.As(results[1].Err, &apiErr) {
+		t.Errorf("Expected an API error, got %v", results[1].Err)
+	}
+}
+
+func TestRunBatchStopOnError(t *testing.T) {
+	boom := errors.New("boom")
+	var calls atomic.Int32
+	call := func(ctx context.Context, req int, _ ...option.RequestOption) (int, error) {
+		calls.Add(1)
+		if req == 0 {
+			return 0, boom
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		return req, nil
+	}
+	reqs := make([]int, 100)
+	for i := range reqs {
+		reqs[i] = i
+	}
+	results, err := openai.RunBatch(context.Background(), reqs, call, openai.BatchOptions{Concurrency: 2, StopOnError: true})
+	if !errors.Is(err, boom) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, boom)
+	}
+	if !errors.Is(results[99].Err, boom) {
+		t.Errorf("Expected unstarted requests to fail with %v, got %v", boom, results[99].Err)
+	}
+	if n := calls.Load(); n > 4 {
+		t.Errorf("Expected the batch to stop early, made %d calls", n)
+	}
+}
+
+func TestRunBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	call := func(ctx context.Context, req int, _ ...option.RequestOption) (int, error) {
+		if req == 2 {
+			cancel()
+		}
+		return req, nil
+	}
+	reqs := make([]int, 50)
+	for i := range reqs {
+		reqs[i] = i
+	}
+	results, err := openai.RunBatch(ctx, reqs, call, openai.BatchOptions{Concurrency: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, context.Canceled)
+	}
+	if results[0].Err != nil || !errors.Is(results[49].Err, context.Canceled) {
+		t.Errorf("Unexpected results: %v %v", results[0].Err, results[49].Err)
+	}
+}
@@ -0,0 +1,266 @@
+package openai
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache - storage for cached API responses.
+//
+// Only deterministic calls are cached: embeddings, moderations, model retrieval, and
+// chat completions and completions which set a seed or a temperature of 0.
+// Keys are a hash of the request URL, its canonical JSON body, and the credentials and headers
+// it is sent with, so clients and requests with different identities do not share responses.
+type Cache interface {
+	// Get returns the value stored for key, and false if there is none or it has expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value for key. A zero ttl means the value does not expire.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// WithCache caches the responses of deterministic calls in cache for ttl. A zero ttl never expires.
+// Use option.WithCacheBypass to skip the cache for a single request.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		if cache == nil {
+			return errors.New("openai: cache must not be nil")
+		}
+		c.Cache = cache
+		c.CacheTTL = ttl
+		return nil
+	}
+}
+
+// cacheableRequest is implemented by request bodies whose responses may be cached.
+type cacheableRequest interface {
+	cacheable() bool
+}
+
+// cacheableEndpoint is implemented by endpoints whose body-less requests may be cached.
+type cacheableEndpoint interface {
+	cacheable(method string, path string) bool
+}
+
+// cacheable reports whether the response to a request may be cached. Requests made with
+// Client.Do and Client.DoRaw are not cached, nor are responses which are not decoded from JSON.
+func cacheable(e endpointI, method string, path string, body any, result any) bool {
+	if _, ok := e.(*rawEndpoint); ok {
+		return false
+	}
+	switch result.(type) {
+	case *rawResponse, io.Writer, *string, *[]byte:
+		return false
+	}
+	if cr, ok := body.(cacheableRequest); ok {
+		return cr.cacheable()
+	}
+	if ce, ok := e.(cacheableEndpoint); ok && body == nil {
+		return ce.cacheable(method, path)
+	}
+	return false
+}
+
+// deterministic reports whether sampling parameters make a completion reproducible.
+func deterministic(temperature *float64, seed *int) bool {
+	return seed != nil || (temperature != nil && *temperature == 0)
+}
+
+// cacheKey returns the cache key of a request to u with body, sent by c with the per-request header.
+func (c *Client) cacheKey(method string, u string, body any, header http.Header) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(method + " " + u + "\n"))
+	fmt.Fprintf(h, "%q %q %q\n", c.authToken, c.OrganizationID, c.ProjectID)
+	headers := http.Header{}
+	for k, v := range c.DefaultHeaders {
+		headers[k] = v
+	}
+	for k, v := range header {
+		headers[k] = v
+	}
+	// Idempotency keys are unique per request and do not change the response.
+	headers.Del("Idempotency-Key")
+	for _, k := range sortedKeys(headers) {
+		fmt.Fprintf(h, "%s: %q\n", k, headers[k])
+	}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		// Decoding and re-encoding sorts object keys, making the body canonical.
+		// Numbers are decoded as json.Number so large integers keep distinct keys.
+		var v any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return "", err
+		}
+		if data, err = json.Marshal(v); err != nil {
+			return "", err
+		}
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cachedResponseMeta describes a response served from the cache.
+func cachedResponseMeta() *ResponseMeta {
+	return &ResponseMeta{StatusCode: http.StatusOK, Header: http.Header{}, Cached: true}
+}
+
+// MemoryCache - an in-memory Cache which evicts the least recently used entries.
+type MemoryCache struct {
+	maxEntries int
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an in-memory cache holding up to maxEntries responses.
+// A maxEntries of zero means no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := el.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && !m.now().Before(entry.expires) {
+		m.lru.Remove(el)
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	m.lru.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := &memoryEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = m.now().Add(ttl)
+	}
+	if el, ok := m.entries[key]; ok {
+		el.Value = entry
+		m.lru.MoveToFront(el)
+		return nil
+	}
+	m.entries[key] = m.lru.PushFront(entry)
+	if m.maxEntries > 0 && m.lru.Len() > m.maxEntries {
+		oldest := m.lru.Back()
+		m.lru.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached responses, including expired ones not yet evicted.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+// DiskCache - a Cache which stores each response in a file, surviving process restarts.
+type DiskCache struct {
+	dir string
+	now func() time.Time
+}
+
+type diskEntry struct {
+	Expires time.Time       `json:"expires,omitempty"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// NewDiskCache creates a cache storing responses in dir, which is created if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir, now: time.Now}, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
+
+// Get implements Cache.
+func (d *DiskCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// A corrupt entry is treated as a miss and overwritten by the next Set.
+		return nil, false, nil
+	}
+	if !entry.Expires.IsZero() && !d.now().Before(entry.Expires) {
+		os.Remove(d.path(key))
+		return nil, false, nil
+	}
+	return entry.Value, true, nil
+}
+
+// Set implements Cache. Values must be JSON.
+func (d *DiskCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := diskEntry{Value: value}
+	if ttl > 0 {
+		entry.Expires = d.now().Add(ttl)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so concurrent readers never see a partial entry.
+	tmp, err := os.CreateTemp(d.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
@@ -0,0 +1,203 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestCacheEmbeddings(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client(openai.WithCache(openai.NewMemoryCache(100), time.Hour))
+
+	req := openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"}
+	first, err := client.Embeddings().CreateEmbeddings(&req)
+	if err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	var meta openai.ResponseMeta
+	ctx := openai.CaptureResponseMeta(context.Background(), &meta)
+	second, err := client.Embeddings().CreateEmbeddingsWithContext(ctx, &req)
+	if err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	if !meta.Cached || len(second.Data) != 1 || second.Data[0].Embedding[0] != first.Data[0].Embedding[0] {
+		t.Errorf("Expected a cached response, got %+v %+v", meta, second)
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", 1)
+
+	if _, err := client.Embeddings().CreateEmbeddings(&req, option.WithCacheBypass()); err != nil {
+		t.Error(err, "CreateEmbeddings error")
+	}
+	req.Input = "other"
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Error(err, "CreateEmbeddings error")
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", 3)
+}
+
+func TestCacheKeyIdentity(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	cache := openai.NewMemoryCache(100)
+	client := fs.Client(openai.WithCache(cache, time.Hour))
+
+	req := openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"}
+	for i, c := range []struct {
+		client  *openai.Client
+		opts    []option.RequestOption
+		wantErr error
+	}{
+		{client, nil, nil},
+		// The fake server rejects other keys, which shows the request was not served from the cache.
+		{fs.Client(openai.WithAPIKey("other-key"), openai.WithCache(cache, time.Hour)), nil, openai.ErrAuthentication},
+		{fs.Client(openai.WithOrganization("org_other"), openai.WithCache(cache, time.Hour)), nil, nil},
+		{client, []option.RequestOption{option.WithProject("proj_other")}, nil},
+		{client, []option.RequestOption{option.WithHeader("X-Tenant", "other")}, nil},
+	} {
+		_, err := c.client.Embeddings().CreateEmbeddings(&req, c.opts...)
+		if !errors.Is(err, c.wantErr) {
+			t.Fatalf("Unexpected error: %v, expected: %v", err, c.wantErr)
+		}
+		fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", i+1)
+	}
+
+	// Idempotency keys do not prevent cache hits.
+	if _, err := client.Embeddings().CreateEmbeddings(&req, option.WithIdempotencyKey("key_123")); err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", 5)
+}
+
+func TestCacheChatCompletionDeterminism(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client(openai.WithCache(openai.NewMemoryCache(100), 0))
+
+	temperature := 0.7
+	req := openai.ChatCompletionRequest{Model: "gpt-4o", Temperature: &temperature}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Chat().CreateChatCompletion(&req); err != nil {
+			t.Fatal(err, "CreateChatCompletion error")
+		}
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/chat/completions", 2)
+
+	temperature = 0
+	for i := 0; i < 2; i++ {
+		if _, err := client.Chat().CreateChatCompletion(&req); err != nil {
+			t.Fatal(err, "CreateChatCompletion error")
+		}
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/chat/completions", 3)
+}
+
+func TestCacheTTL(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client(openai.WithCache(openai.NewMemoryCache(100), 20*time.Millisecond))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Models().RetrieveModel("gpt-4o"); err != nil {
+			t.Fatal(err, "RetrieveModel error")
+		}
+	}
+	fs.AssertCalled(t, http.MethodGet, "/v1/models/gpt-4o", 1)
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.Models().RetrieveModel("gpt-4o"); err != nil {
+		t.Fatal(err, "RetrieveModel error")
+	}
+	fs.AssertCalled(t, http.MethodGet, "/v1/models/gpt-4o", 2)
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := openai.NewMemoryCache(2)
+	cache.Set(ctx, "a", []byte("1"), 0)
+	cache.Set(ctx, "b", []byte("2"), 0)
+	cache.Get(ctx, "a")
+	cache.Set(ctx, "c", []byte("3"), 0)
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Error("Expected a recently used entry to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len mismatch. Got %d. Expected 2", cache.Len())
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		// A new cache and client each time, as in separate processes.
+		cache, err := openai.NewDiskCache(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := fs.Client(openai.WithCache(cache, time.Hour))
+		model, err := client.Models().RetrieveModel("gpt-4o")
+		if err != nil || model.ID != "gpt-4o" {
+			t.Fatalf("RetrieveModel mismatch: %+v %v", model, err)
+		}
+	}
+	fs.AssertCalled(t, http.MethodGet, "/v1/models/gpt-4o", 1)
+}
+
+func TestCacheSkipsRawRequests(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client(openai.WithCache(openai.NewMemoryCache(100), time.Hour))
+
+	req := openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"}
+	for i := 0; i < 2; i++ {
+		res, err := client.DoRaw(context.Background(), "POST", "/embeddings", &req)
+		if err != nil || res == nil {
+			t.Fatalf("Unexpected DoRaw result: %v %v", res, err)
+		}
+		res.Body.Close()
+
+		var buf bytes.Buffer
+		if err := client.Do(context.Background(), "POST", "/embeddings", &req, &buf); err != nil || buf.Len() == 0 {
+			t.Errorf("Unexpected Do result: %q %v", buf.String(), err)
+		}
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", 4)
+
+	// Responses cached by endpoint methods are not served to Do either.
+	var raw string
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	if err := client.Do(context.Background(), "POST", "/embeddings", &req, &raw); err != nil || raw == "" {
+		t.Errorf("Unexpected Do result: %q %v", raw, err)
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", 6)
+}
+
+func TestCacheKeyLargeIntegers(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client(openai.WithCache(openai.NewMemoryCache(100), time.Hour))
+
+	// Seeds which are equal as float64 are different requests.
+	for _, seed := range []int{1 << 53, 1<<53 + 1} {
+		req := openai.ChatCompletionRequest{Model: "gpt-4o", Seed: &seed, Messages: []openai.ChatMessage{openai.UserMessage("Hi")}}
+		if _, err := client.Chat().CreateChatCompletion(&req); err != nil {
+			t.Fatal(err, "CreateChatCompletion error")
+		}
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/chat/completions", 2)
+}
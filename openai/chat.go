@@ -1,5 +1,13 @@
 package openai
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
 const ChatEndpointPath = "/chat/"
 
 // Completions Endpoint
@@ -8,28 +16,28 @@ const ChatEndpointPath = "/chat/"
 //	 and can also return the probabilities of alternative tokens at each position.
 type ChatEndpoint struct {
 	*endpoint
+	fallback *FallbackPolicy
 }
 
 // Completions Endpoint
 func (c *Client) Chat() *ChatEndpoint {
-	return &ChatEndpoint{newEndpoint(c, ChatEndpointPath)}
+	return &ChatEndpoint{endpoint: newDeploymentEndpoint(c, ChatEndpointPath)}
+}
+
+// WithFallback returns a copy of the endpoint which falls back along policy's chain when a request fails.
+func (e *ChatEndpoint) WithFallback(policy *FallbackPolicy) *ChatEndpoint {
+	return &ChatEndpoint{endpoint: e.endpoint, fallback: policy}
 }
 
 type ChatCompletionRequest struct {
 	// ID of the model to use. See the [model endpoint compatibility]: https://platform.openai.com/docs/models/model-endpoint-compatibility table for details on which models work with the Chat API.
 	Model string `json:"model" binding:"required"`
 	// A list of messages describing the conversation so far.
-	Messages []string `json:"messages" binding:"required"`
-	// The role of the author of this message. One of system, user, or assistant.
-	Role string `json:"role" binding:"required"`
-	// The contents of the message.
-	Content string `json:"content" binding:"required"`
-	// The name of the author of this message. May contain a-z, A-Z, 0-9, and underscores, with a maximum length of 64 characters.
-	Name string `json:"name,omitempty"`
+	Messages []ChatMessage `json:"messages" binding:"required"`
 	// Defaults to 1
 	// What sampling temperature to use, between 0 and 2. Higher values like 0.8 will make the output more random, while lower values like 0.2 will make it more focused and deterministic.
 	// We generally recommend altering this or top_p but not both.
-	Temperature int `json:"temperature,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
 	// Defaults to 1
 	// An alternative to sampling with temperature, called nucleus sampling, where the model considers the results of the tokens with top_p probability mass. So 0.1 means only the tokens comprising the top 10% probability mass are considered.
 	// We generally recommend altering this or temperature but not both.
@@ -40,6 +48,8 @@ type ChatCompletionRequest struct {
 	// Defaults to false
 	// If set, partial message deltas will be sent, like in ChatGPT. Tokens will be sent as data-only server-sent events as they become available, with the stream terminated by a data: [DONE] message. See the OpenAI Cookbook for example code.
 	Stream bool `json:"stream,omitempty"`
+	// Options for streaming responses. Only set this when Stream is true.
+	StreamOptions *ChatCompletionStreamOptions `json:"stream_options,omitempty"`
 	// Defaults to null
 	// Up to 4 sequences where the API will stop generating further tokens.
 	Stop []string `json:"stop,omitempty"`
@@ -61,32 +71,144 @@ type ChatCompletionRequest struct {
 	LogitBias map[string]string `json:"logit_bias,omitempty"`
 	// A unique identifier representing your end-user, which can help OpenAI to monitor and detect abuse. Learn more.
 	User string `json:"user,omitempty"`
+	// Defaults to null
+	// If specified, the system will make a best effort to sample deterministically,
+	// such that repeated requests with the same seed and parameters should return the same result.
+	Seed *int `json:"seed,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (c ChatCompletionRequest) MarshalJSON() ([]byte, error) {
+	type alias ChatCompletionRequest
+	return marshalWithExtra(alias(c), c.ExtraFields)
+}
+
+func (r *ChatCompletionRequest) requestModel() string {
+	return r.Model
+}
+
+func (r *ChatCompletionRequest) cacheable() bool {
+	return !r.Stream && deterministic(r.Temperature, r.Seed)
+}
+
+func (r *ChatCompletionRequest) estimateTokens() int {
+	tokens := 0
+	for i := range r.Messages {
+		tokens += r.Messages[i].estimateTokens()
+	}
+	n := r.N
+	if n < 1 {
+		n = 1
+	}
+	return tokens + r.MaxTokens*n
 }
 
 type ChatCompletionResponse struct {
 	Id      string `json:"id"`
 	Object  string `json:"object"`
 	Created int    `json:"created"`
-	Choices []struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	// The model used for the chat completion.
+	Model string `json:"model"`
+	// The backend configuration the model ran with, which changes with the results of seeded requests.
+	SystemFingerprint string                 `json:"system_fingerprint,omitempty"`
+	Choices           []ChatCompletionChoice `json:"choices"`
+	Usage             ChatCompletionUsage    `json:"usage"`
+	// Azure OpenAI content filtering results for the prompt.
+	PromptFilterResults []PromptFilterResult `json:"prompt_filter_results,omitempty"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// ChatCompletionChoice - a chat completion choice.
+type ChatCompletionChoice struct {
+	Index   int         `json:"index"`
+	Message ChatMessage `json:"message"`
+	// The reason the model stopped generating tokens: stop, length, tool_calls or content_filter.
+	FinishReason string `json:"finish_reason"`
+	// Log probability information for the choice, when requested.
+	Logprobs *ChatLogprobs `json:"logprobs,omitempty"`
+	// Azure OpenAI content filtering results for the choice.
+	ContentFilterResults *ContentFilterResults `json:"content_filter_results,omitempty"`
+}
+
+// ChatLogprobs - log probability information for a choice.
+type ChatLogprobs struct {
+	// Log probabilities of the message content tokens.
+	Content []ChatTokenLogprob `json:"content"`
+	// Log probabilities of the message refusal tokens.
+	Refusal []ChatTokenLogprob `json:"refusal,omitempty"`
+}
+
+// ChatTokenLogprob - the log probability of a token, with the most likely alternatives.
+type ChatTokenLogprob struct {
+	ChatTopLogprob
+	// Most likely tokens at this position, when top_logprobs is requested.
+	TopLogprobs []ChatTopLogprob `json:"top_logprobs"`
+}
+
+// ChatTopLogprob - the log probability of a token.
+type ChatTopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	// UTF-8 bytes of the token, for tokens which are not valid UTF-8 on their own.
+	Bytes []int `json:"bytes"`
+}
+
+// ChatCompletionUsage - usage statistics for a chat completion request.
+type ChatCompletionUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// RawJSON returns the JSON the ChatCompletionResponse was decoded from.
+func (c *ChatCompletionResponse) RawJSON() json.RawMessage {
+	return c.rawJSON
+}
+
+func (c *ChatCompletionResponse) UnmarshalJSON(data []byte) error {
+	type alias ChatCompletionResponse
+	extra, err := unmarshalWithExtra(data, (*alias)(c))
+	c.ExtraFields = extra
+	c.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (c ChatCompletionResponse) MarshalJSON() ([]byte, error) {
+	type alias ChatCompletionResponse
+	return marshalWithExtra(alias(c), c.ExtraFields)
+}
+
+func (r *ChatCompletionResponse) tokenUsage() TokenUsage {
+	return TokenUsage{
+		PromptTokens:     r.Usage.PromptTokens,
+		CompletionTokens: r.Usage.CompletionTokens,
+		TotalTokens:      r.Usage.TotalTokens,
+	}
 }
 
 // Creates a model response for the given chat conversation.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/chat/create
-func (e *ChatEndpoint) CreateChatCompletion(req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
-	var resp ChatCompletionResponse
-	err := e.do(e, "POST", "completions", req, nil, &resp)
-	return &resp, err
+func (e *ChatEndpoint) CreateChatCompletion(req *ChatCompletionRequest, opts ...option.RequestOption) (*ChatCompletionResponse, error) {
+	return e.CreateChatCompletionWithContext(context.Background(), req, opts...)
+}
+
+// CreateChatCompletionWithContext is like CreateChatCompletion but uses ctx for the request.
+func (e *ChatEndpoint) CreateChatCompletionWithContext(ctx context.Context, req *ChatCompletionRequest, opts ...option.RequestOption) (*ChatCompletionResponse, error) {
+	if req.Stream {
+		return &ChatCompletionResponse{}, errors.New("openai: streaming requests must use CreateChatCompletionStream")
+	}
+	return runWithFallback(ctx, e.fallback, req.Model, opts, func(ctx context.Context, model string, opts []option.RequestOption) (*ChatCompletionResponse, error) {
+		r := *req
+		r.Model = model
+		var resp ChatCompletionResponse
+		err := e.do(ctx, e, "POST", "completions", &r, nil, &resp, opts...)
+		return &resp, err
+	})
 }
@@ -0,0 +1,225 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChatCompletionAccumulator - reconstructs a complete ChatCompletionResponse from streamed chunks.
+//
+// Content, refusals, tool call arguments and log probabilities are concatenated per choice,
+// and the finish reasons and usage of the stream are kept:
+//
+//	acc := &openai.ChatCompletionAccumulator{
+//		OnToolCallDone: func(choice int, call openai.ChatToolCall) {
+//			log.Printf("calling %s(%s)", call.Function.Name, call.Function.Arguments)
+//		},
+//	}
+//	for stream.Next() {
+//		if err := acc.Add(stream.Value()); err != nil {
+//			return err
+//		}
+//	}
+//	if err := stream.Err(); err != nil {
+//		return err
+//	}
+//	resp := acc.Response()
+type ChatCompletionAccumulator struct {
+	// Called once the content of a choice is complete: when the model finishes the choice,
+	// or starts calling tools after generating content.
+	OnContentDone func(choice int, content string)
+	// Called once the refusal of a choice is complete.
+	OnRefusalDone func(choice int, refusal string)
+	// Called once the arguments of a tool call are complete: when the model starts the next
+	// tool call of the choice, or finishes the choice.
+	OnToolCallDone func(choice int, call ChatToolCall)
+
+	resp    ChatCompletionResponse
+	choices []*choiceAccumulator
+}
+
+// choiceAccumulator - the state of a choice being accumulated.
+type choiceAccumulator struct {
+	content     strings.Builder
+	refusal     strings.Builder
+	toolCalls   []ChatToolCall
+	arguments   []*strings.Builder
+	contentDone bool
+	refusalDone bool
+	// Index of the tool call being streamed, or -1.
+	toolCall int
+	// Number of tool calls completed.
+	toolCallsDone int
+	finished      bool
+}
+
+// Add adds a chunk of the stream. Chunks must be added in the order they were received.
+func (a *ChatCompletionAccumulator) Add(chunk *ChatCompletionChunk) error {
+	if chunk == nil {
+		return errors.New("openai: nil chat completion chunk")
+	}
+	// Azure OpenAI streams the prompt filter results first, in a chunk without an ID or model.
+	if chunk.Id != "" {
+		if a.resp.Id == "" {
+			a.resp.Id = chunk.Id
+		} else if chunk.Id != a.resp.Id {
+			return fmt.Errorf("openai: chunk %s does not belong to chat completion %s", chunk.Id, a.resp.Id)
+		}
+	}
+	a.resp.Object = "chat.completion"
+	if a.resp.Created == 0 {
+		a.resp.Created = chunk.Created
+	}
+	if a.resp.Model == "" {
+		a.resp.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != "" {
+		a.resp.SystemFingerprint = chunk.SystemFingerprint
+	}
+	a.resp.PromptFilterResults = append(a.resp.PromptFilterResults, chunk.PromptFilterResults...)
+	for name, value := range chunk.ExtraFields {
+		if a.resp.ExtraFields == nil {
+			a.resp.ExtraFields = map[string]json.RawMessage{}
+		}
+		a.resp.ExtraFields[name] = value
+	}
+	if chunk.Usage != nil {
+		a.resp.Usage = *chunk.Usage
+	}
+	for i := range chunk.Choices {
+		if err := a.addChoice(&chunk.Choices[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// choice returns the accumulated choice and its state for index, adding any missing choices.
+func (a *ChatCompletionAccumulator) choice(index int) (*ChatCompletionChoice, *choiceAccumulator) {
+	for len(a.choices) <= index {
+		a.resp.Choices = append(a.resp.Choices, ChatCompletionChoice{Index: len(a.choices)})
+		a.choices = append(a.choices, &choiceAccumulator{toolCall: -1})
+	}
+	return &a.resp.Choices[index], a.choices[index]
+}
+
+func (a *ChatCompletionAccumulator) addChoice(delta *ChatCompletionChunkChoice) error {
+	if delta.Index < 0 {
+		return fmt.Errorf("openai: invalid chat completion choice index %d", delta.Index)
+	}
+	choice, state := a.choice(delta.Index)
+	if state.finished {
+		return fmt.Errorf("openai: chunk for finished chat completion choice %d", delta.Index)
+	}
+	if delta.Delta.Role != "" {
+		choice.Message.Role = delta.Delta.Role
+	}
+	state.content.WriteString(delta.Delta.Content)
+	state.refusal.WriteString(delta.Delta.Refusal)
+	if len(delta.Delta.ToolCalls) > 0 {
+		a.contentDone(choice.Index, state)
+	}
+	for _, d := range delta.Delta.ToolCalls {
+		if err := a.addToolCall(choice.Index, state, &d); err != nil {
+			return err
+		}
+	}
+	if delta.Logprobs != nil {
+		if choice.Logprobs == nil {
+			choice.Logprobs = &ChatLogprobs{}
+		}
+		choice.Logprobs.Content = append(choice.Logprobs.Content, delta.Logprobs.Content...)
+		choice.Logprobs.Refusal = append(choice.Logprobs.Refusal, delta.Logprobs.Refusal...)
+	}
+	if delta.ContentFilterResults != nil {
+		choice.ContentFilterResults = delta.ContentFilterResults
+	}
+	choice.Message.Content = ChatMessageContent{Text: state.content.String()}
+	choice.Message.Refusal = state.refusal.String()
+	if delta.FinishReason != "" {
+		choice.FinishReason = delta.FinishReason
+		state.finished = true
+		a.contentDone(choice.Index, state)
+		a.toolCallDone(choice.Index, state)
+	}
+	return nil
+}
+
+func (a *ChatCompletionAccumulator) addToolCall(choice int, state *choiceAccumulator, d *ChatToolCallDelta) error {
+	if d.Index < 0 {
+		return fmt.Errorf("openai: invalid tool call index %d", d.Index)
+	}
+	if d.Index != state.toolCall {
+		a.toolCallDone(choice, state)
+		if d.Index < state.toolCallsDone {
+			return fmt.Errorf("openai: chunk for completed tool call %d of choice %d", d.Index, choice)
+		}
+		state.toolCall = d.Index
+	}
+	for len(state.toolCalls) <= d.Index {
+		state.toolCalls = append(state.toolCalls, ChatToolCall{})
+		state.arguments = append(state.arguments, &strings.Builder{})
+	}
+	call := &state.toolCalls[d.Index]
+	if d.ID != "" {
+		call.ID = d.ID
+	}
+	if d.Type != "" {
+		call.Type = d.Type
+	}
+	call.Function.Name += d.Function.Name
+	state.arguments[d.Index].WriteString(d.Function.Arguments)
+	call.Function.Arguments = state.arguments[d.Index].String()
+	a.resp.Choices[choice].Message.ToolCalls = state.toolCalls
+	return nil
+}
+
+func (a *ChatCompletionAccumulator) contentDone(choice int, state *choiceAccumulator) {
+	if !state.contentDone && state.content.Len() > 0 {
+		state.contentDone = true
+		if a.OnContentDone != nil {
+			a.OnContentDone(choice, state.content.String())
+		}
+	}
+	if !state.refusalDone && state.refusal.Len() > 0 {
+		state.refusalDone = true
+		if a.OnRefusalDone != nil {
+			a.OnRefusalDone(choice, state.refusal.String())
+		}
+	}
+}
+
+func (a *ChatCompletionAccumulator) toolCallDone(choice int, state *choiceAccumulator) {
+	if state.toolCall < state.toolCallsDone {
+		return
+	}
+	state.toolCallsDone = state.toolCall + 1
+	if a.OnToolCallDone != nil {
+		a.OnToolCallDone(choice, state.toolCalls[state.toolCall])
+	}
+}
+
+// Response returns the response accumulated so far. It is complete once the stream has ended.
+// The returned response is a copy and is not modified by later calls to Add.
+func (a *ChatCompletionAccumulator) Response() *ChatCompletionResponse {
+	resp := a.resp
+	resp.PromptFilterResults = append([]PromptFilterResult(nil), a.resp.PromptFilterResults...)
+	resp.Choices = make([]ChatCompletionChoice, len(a.resp.Choices))
+	for i, c := range a.resp.Choices {
+		c.Message.ToolCalls = append([]ChatToolCall(nil), c.Message.ToolCalls...)
+		if c.Logprobs != nil {
+			logprobs := *c.Logprobs
+			c.Logprobs = &logprobs
+		}
+		resp.Choices[i] = c
+	}
+	if a.resp.ExtraFields != nil {
+		resp.ExtraFields = make(map[string]json.RawMessage, len(a.resp.ExtraFields))
+		for k, v := range a.resp.ExtraFields {
+			resp.ExtraFields[k] = v
+		}
+	}
+	return &resp
+}
@@ -0,0 +1,166 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestChatCompletionAccumulator(t *testing.T) {
+	// Two choices interleaved: the first answers with content, the second calls two tools.
+	chunks := []string{
+		`{"id":"c1","created":1,"model":"gpt-4o","system_fingerprint":"fp_1","service_tier":"default","choices":[{"index":0,"delta":{"role":"assistant","content":""}},{"index":1,"delta":{"role":"assistant"}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"content":"Hello"},"logprobs":{"content":[{"token":"Hello","logprob":-0.1,"bytes":null,"top_logprobs":[]}]}}]}`,
+		`{"id":"c1","choices":[{"index":1,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"content":" world"},"logprobs":{"content":[{"token":" world","logprob":-0.2,"bytes":null,"top_logprobs":[]}]}}]}`,
+		`{"id":"c1","choices":[{"index":1,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":1,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":1,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`,
+		`{"id":"c1","choices":[{"index":1,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"id":"c1","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+	}
+	var events []string
+	acc := &openai.ChatCompletionAccumulator{
+		OnContentDone: func(choice int, content string) {
+			events = append(events, fmt.Sprintf("content %d %s", choice, content))
+		},
+		OnToolCallDone: func(choice int, call openai.ChatToolCall) {
+			events = append(events, fmt.Sprintf("tool %d %s %s%s", choice, call.ID, call.Function.Name, call.Function.Arguments))
+		},
+	}
+	for _, data := range chunks {
+		var chunk openai.ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatal(err)
+		}
+		if err := acc.Add(&chunk); err != nil {
+			t.Fatal(err, "Add error")
+		}
+	}
+	want := []string{
+		`tool 1 call_1 get_weather{"city":"Paris"}`,
+		"content 0 Hello world",
+		"tool 1 call_2 get_time{}",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("Events mismatch. Got %q. Expected %q", events, want)
+	}
+
+	resp := acc.Response()
+	if resp.Id != "c1" || resp.Object != "chat.completion" || resp.Model != "gpt-4o" || resp.Usage.TotalTokens != 15 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if resp.SystemFingerprint != "fp_1" || string(resp.ExtraFields["service_tier"]) != `"default"` {
+		t.Errorf("Unexpected extra fields: %v", resp.ExtraFields)
+	}
+	if len(resp.Choices) != 2 {
+		t.Fatalf("Unexpected choices: %+v", resp.Choices)
+	}
+	first := resp.Choices[0]
+	if first.Message.Role != "assistant" || first.Message.Content.String() != "Hello world" || first.FinishReason != "stop" {
+		t.Errorf("Unexpected first choice: %+v", first)
+	}
+	if first.Logprobs == nil || len(first.Logprobs.Content) != 2 || first.Logprobs.Content[1].Token != " world" {
+		t.Errorf("Unexpected logprobs: %+v", first.Logprobs)
+	}
+	second := resp.Choices[1]
+	if second.FinishReason != "tool_calls" || len(second.Message.ToolCalls) != 2 || second.Message.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("Unexpected second choice: %+v", second)
+	}
+
+	if err := acc.Add(&openai.ChatCompletionChunk{Id: "c2"}); err == nil {
+		t.Error("Expected error for a chunk of another completion")
+	}
+	if err := acc.Add(&openai.ChatCompletionChunk{Id: "c1", Choices: []openai.ChatCompletionChunkChoice{{Index: 0}}}); err == nil {
+		t.Error("Expected error for a chunk of a finished choice")
+	}
+}
+
+func TestChatCompletionAccumulatorAzure(t *testing.T) {
+	// Azure OpenAI sends the prompt filter results in a first chunk without an ID, model or choices.
+	chunks := []string{
+		`{"id":"","object":"","created":0,"model":"","prompt_filter_results":[{"prompt_index":0,"content_filter_results":{"hate":{"filtered":false,"severity":"safe"}}}],"choices":[]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o-2024-08-06","system_fingerprint":"fp_1","choices":[{"index":0,"delta":{"role":"assistant","content":""},"content_filter_results":{}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{"content":"Hi"},"content_filter_results":{"hate":{"filtered":false,"severity":"safe"}}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o-2024-08-06","choices":[{"index":0,"delta":{},"finish_reason":"stop","content_filter_results":{}}]}`,
+	}
+	acc := &openai.ChatCompletionAccumulator{}
+	for _, data := range chunks {
+		var chunk openai.ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatal(err)
+		}
+		if err := acc.Add(&chunk); err != nil {
+			t.Fatal(err, "Add error")
+		}
+	}
+	resp := acc.Response()
+	if resp.Id != "chatcmpl-1" || resp.Model != "gpt-4o-2024-08-06" || resp.Created != 1 || resp.SystemFingerprint != "fp_1" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if len(resp.PromptFilterResults) != 1 || resp.PromptFilterResults[0].ContentFilterResults.Hate == nil {
+		t.Errorf("Unexpected prompt filter results: %+v", resp.PromptFilterResults)
+	}
+	if len(resp.Choices) != 1 || resp.Choices[0].Message.Content.String() != "Hi" || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("Unexpected choices: %+v", resp.Choices)
+	}
+	if len(resp.ExtraFields) != 0 {
+		t.Errorf("Unexpected extra fields: %v", resp.ExtraFields)
+	}
+}
+
+func TestChatCompletionAccumulatorStream(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	arguments := `{"query": "streaming accumulators", "limit": 10}`
+	fs.Script("/v1/chat/completions", map[string]any{
+		"id":    "chatcmpl-1",
+		"model": "gpt-4o",
+		"choices": []any{
+			map[string]any{"index": 0, "message": map[string]any{"role": "assistant", "content": "Let me search for that."}, "finish_reason": "stop"},
+			map[string]any{"index": 1, "finish_reason": "tool_calls", "message": map[string]any{
+				"role":       "assistant",
+				"tool_calls": []any{map[string]any{"id": "call_1", "type": "function", "function": map[string]any{"name": "search", "arguments": arguments}}},
+			}},
+		},
+		"usage": map[string]any{"prompt_tokens": 3, "completion_tokens": 7, "total_tokens": 10},
+	})
+	stream, err := fs.Client().Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{
+		Model:         "gpt-4o",
+		N:             2,
+		Messages:      []openai.ChatMessage{openai.UserMessage("Search")},
+		StreamOptions: &openai.ChatCompletionStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+
+	var contents, calls []string
+	acc := &openai.ChatCompletionAccumulator{
+		OnContentDone:  func(_ int, content string) { contents = append(contents, content) },
+		OnToolCallDone: func(_ int, call openai.ChatToolCall) { calls = append(calls, call.Function.Arguments) },
+	}
+	for stream.Next() {
+		if err := acc.Add(stream.Value()); err != nil {
+			t.Fatal(err, "Add error")
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err, "Stream error")
+	}
+	resp := acc.Response()
+	if resp.Choices[0].Message.Content.String() != "Let me search for that." || resp.Usage.TotalTokens != 10 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if len(contents) != 1 || len(calls) != 1 || calls[0] != arguments {
+		t.Errorf("Unexpected callbacks: %q %q", contents, calls)
+	}
+	if resp.Choices[1].Message.ToolCalls[0].ID != "call_1" {
+		t.Errorf("Unexpected tool calls: %+v", resp.Choices[1].Message.ToolCalls)
+	}
+}
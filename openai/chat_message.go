@@ -0,0 +1,253 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Roles of the authors of chat messages.
+const (
+	ChatRoleSystem    = "system"
+	ChatRoleDeveloper = "developer"
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+	ChatRoleTool      = "tool"
+)
+
+// Types of chat message content parts.
+const (
+	ChatContentPartText       = "text"
+	ChatContentPartImageURL   = "image_url"
+	ChatContentPartInputAudio = "input_audio"
+	ChatContentPartFile       = "file"
+	ChatContentPartRefusal    = "refusal"
+)
+
+// Levels of detail at which the model looks at an image.
+const (
+	ImageDetailAuto = "auto"
+	ImageDetailLow  = "low"
+	ImageDetailHigh = "high"
+)
+
+// ChatMessage - a message of a chat conversation.
+type ChatMessage struct {
+	// The role of the author of this message. One of system, developer, user, assistant or tool.
+	Role string `json:"role"`
+	// The contents of the message, either text or a list of content parts.
+	Content ChatMessageContent `json:"content"`
+	// An optional name for the participant. Provides the model information to differentiate between participants of the same role.
+	Name string `json:"name,omitempty"`
+	// Tool call that this message is responding to. Required for tool messages.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	// The refusal message by the assistant.
+	Refusal string `json:"refusal,omitempty"`
+	// The tool calls generated by the model, in assistant messages.
+	ToolCalls []ChatToolCall `json:"tool_calls,omitempty"`
+}
+
+// MarshalJSON encodes empty content as null for assistant messages which only carry
+// tool calls or a refusal, and as an empty string otherwise.
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	type alias ChatMessage
+	if m.Role == ChatRoleAssistant && m.Content.empty() && (len(m.ToolCalls) > 0 || m.Refusal != "") {
+		return json.Marshal(struct {
+			alias
+			Content *ChatMessageContent `json:"content"`
+		}{alias: alias(m)})
+	}
+	return json.Marshal(alias(m))
+}
+
+// ChatToolCall - a call of a tool generated by the model.
+type ChatToolCall struct {
+	// The ID of the tool call, referenced by the tool message carrying its result.
+	ID string `json:"id"`
+	// The type of the tool. Currently, only function is supported.
+	Type     string           `json:"type"`
+	Function ChatFunctionCall `json:"function"`
+}
+
+// ChatFunctionCall - the function the model called.
+type ChatFunctionCall struct {
+	Name string `json:"name"`
+	// The arguments to call the function with, as JSON generated by the model.
+	// The model does not always generate valid JSON; validate the arguments before calling the function.
+	Arguments string `json:"arguments"`
+}
+
+// ChatMessageContent - the contents of a chat message: plain text, or a list of typed parts
+// for multimodal input. It is encoded as a JSON string when Parts is nil, and as an array otherwise.
+type ChatMessageContent struct {
+	Text  string
+	Parts []ChatContentPart
+}
+
+// String returns the text of the content, concatenating the text of its parts.
+func (c ChatMessageContent) String() string {
+	if c.Parts == nil {
+		return c.Text
+	}
+	var sb strings.Builder
+	for _, p := range c.Parts {
+		sb.WriteString(p.Text)
+	}
+	return sb.String()
+}
+
+func (c ChatMessageContent) empty() bool {
+	return c.Parts == nil && c.Text == ""
+}
+
+func (c ChatMessageContent) MarshalJSON() ([]byte, error) {
+	if c.Parts != nil {
+		return json.Marshal(c.Parts)
+	}
+	return json.Marshal(c.Text)
+}
+
+func (c *ChatMessageContent) UnmarshalJSON(data []byte) error {
+	*c = ChatMessageContent{}
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		return json.Unmarshal(data, &c.Text)
+	case len(data) > 0 && data[0] == '[':
+		return json.Unmarshal(data, &c.Parts)
+	}
+	return errors.New("openai: chat message content must be a string or an array of parts")
+}
+
+// ChatContentPart - a typed part of a multimodal chat message.
+type ChatContentPart struct {
+	// The type of the part. One of text, image_url, input_audio, file or refusal.
+	Type string `json:"type"`
+	// The text of a text part.
+	Text string `json:"text,omitempty"`
+	// The image of an image_url part.
+	ImageURL *ChatImageURL `json:"image_url,omitempty"`
+	// The audio of an input_audio part.
+	InputAudio *ChatInputAudio `json:"input_audio,omitempty"`
+	// The file of a file part.
+	File *ChatFile `json:"file,omitempty"`
+	// The refusal message of a refusal part, in assistant messages.
+	Refusal string `json:"refusal,omitempty"`
+}
+
+// MarshalJSON always encodes the text of text parts, even when empty.
+func (p ChatContentPart) MarshalJSON() ([]byte, error) {
+	type alias ChatContentPart
+	if p.Type == ChatContentPartText {
+		return json.Marshal(struct {
+			alias
+			Text string `json:"text"`
+		}{alias(p), p.Text})
+	}
+	return json.Marshal(alias(p))
+}
+
+// ChatImageURL - an image sent to the model.
+type ChatImageURL struct {
+	// Either a URL of the image or the base64 encoded image data as a data URL.
+	URL string `json:"url"`
+	// Defaults to auto
+	// The detail level of the image. One of auto, low or high.
+	Detail string `json:"detail,omitempty"`
+}
+
+// ChatInputAudio - audio sent to the model.
+type ChatInputAudio struct {
+	// Base64 encoded audio data.
+	Data string `json:"data"`
+	// The format of the encoded audio data. One of wav or mp3.
+	Format string `json:"format"`
+}
+
+// ChatFile - a file sent to the model.
+type ChatFile struct {
+	// The ID of an uploaded file.
+	FileID string `json:"file_id,omitempty"`
+	// The base64 encoded file data, used instead of FileID.
+	FileData string `json:"file_data,omitempty"`
+	// The name of the file, used with FileData.
+	Filename string `json:"filename,omitempty"`
+}
+
+// SystemMessage creates a system message.
+func SystemMessage(content string) ChatMessage {
+	return ChatMessage{Role: ChatRoleSystem, Content: ChatMessageContent{Text: content}}
+}
+
+// DeveloperMessage creates a developer message, which replaces system messages for reasoning models.
+func DeveloperMessage(content string) ChatMessage {
+	return ChatMessage{Role: ChatRoleDeveloper, Content: ChatMessageContent{Text: content}}
+}
+
+// UserMessage creates a user message with text content.
+func UserMessage(content string) ChatMessage {
+	return ChatMessage{Role: ChatRoleUser, Content: ChatMessageContent{Text: content}}
+}
+
+// UserMessageParts creates a user message with multimodal content.
+//
+//	openai.UserMessageParts(
+//		openai.TextPart("What is in this image?"),
+//		openai.ImagePart("https://example.com/cat.png", openai.ImageDetailLow),
+//	)
+func UserMessageParts(parts ...ChatContentPart) ChatMessage {
+	return ChatMessage{Role: ChatRoleUser, Content: ChatMessageContent{Parts: parts}}
+}
+
+// AssistantMessage creates an assistant message, e.g. to replay an earlier turn of the conversation.
+func AssistantMessage(content string) ChatMessage {
+	return ChatMessage{Role: ChatRoleAssistant, Content: ChatMessageContent{Text: content}}
+}
+
+// ToolMessage creates a message carrying the result of the tool call toolCallID.
+func ToolMessage(toolCallID string, content string) ChatMessage {
+	return ChatMessage{Role: ChatRoleTool, Content: ChatMessageContent{Text: content}, ToolCallID: toolCallID}
+}
+
+// TextPart creates a text content part.
+func TextPart(text string) ChatContentPart {
+	return ChatContentPart{Type: ChatContentPartText, Text: text}
+}
+
+// ImagePart creates an image content part from a URL or a data URL. detail may be empty.
+func ImagePart(url string, detail string) ChatContentPart {
+	return ChatContentPart{Type: ChatContentPartImageURL, ImageURL: &ChatImageURL{URL: url, Detail: detail}}
+}
+
+// InputAudioPart creates an audio content part from raw audio data in format, e.g. wav or mp3.
+func InputAudioPart(data []byte, format string) ChatContentPart {
+	return ChatContentPart{
+		Type:       ChatContentPartInputAudio,
+		InputAudio: &ChatInputAudio{Data: base64.StdEncoding.EncodeToString(data), Format: format},
+	}
+}
+
+// FilePart creates a file content part referencing an uploaded file.
+func FilePart(fileID string) ChatContentPart {
+	return ChatContentPart{Type: ChatContentPartFile, File: &ChatFile{FileID: fileID}}
+}
+
+// estimateTokens approximates the prompt tokens of the message. Each message carries a few tokens
+// of framing, and non-text parts count as much as a low detail image.
+func (m *ChatMessage) estimateTokens() int {
+	const messageOverhead = 4
+	const partOverhead = 85
+	tokens := messageOverhead + estimateTextTokens(m.Name) + estimateTextTokens(m.Content.Text)
+	for _, p := range m.Content.Parts {
+		if p.Type == ChatContentPartText {
+			tokens += estimateTextTokens(p.Text)
+		} else {
+			tokens += partOverhead
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,97 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestChatMessageMarshal(t *testing.T) {
+	messages := []openai.ChatMessage{
+		openai.SystemMessage("Be brief."),
+		openai.UserMessageParts(
+			openai.TextPart("What is in this image?"),
+			openai.ImagePart("https://example.com/cat.png", openai.ImageDetailLow),
+			openai.InputAudioPart([]byte("RIFF"), "wav"),
+			openai.FilePart("file-123"),
+		),
+		{Role: openai.ChatRoleAssistant, Refusal: "I can't help with that."},
+		{Role: openai.ChatRoleAssistant, ToolCalls: []openai.ChatToolCall{
+			{ID: "call_123", Type: "function", Function: openai.ChatFunctionCall{Name: "add", Arguments: `{"a":40,"b":2}`}},
+		}},
+		openai.ToolMessage("call_123", "42"),
+		openai.UserMessage(""),
+		openai.UserMessageParts(openai.TextPart("")),
+	}
+	data, err := json.Marshal(messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, data, `[
+		{"role": "system", "content": "Be brief."},
+		{"role": "user", "content": [
+			{"type": "text", "text": "What is in this image?"},
+			{"type": "image_url", "image_url": {"url": "https://example.com/cat.png", "detail": "low"}},
+			{"type": "input_audio", "input_audio": {"data": "UklGRg==", "format": "wav"}},
+			{"type": "file", "file": {"file_id": "file-123"}}
+		]},
+		{"role": "assistant", "content": null, "refusal": "I can't help with that."},
+		{"role": "assistant", "content": null, "tool_calls": [
+			{"id": "call_123", "type": "function", "function": {"name": "add", "arguments": "{\"a\":40,\"b\":2}"}}
+		]},
+		{"role": "tool", "content": "42", "tool_call_id": "call_123"},
+		{"role": "user", "content": ""},
+		{"role": "user", "content": [{"type": "text", "text": ""}]}
+	]`)
+
+	var decoded []openai.ChatMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded[0].Content.String() != "Be brief." || decoded[0].Content.Parts != nil {
+		t.Errorf("Unexpected text content: %+v", decoded[0].Content)
+	}
+	if len(decoded[1].Content.Parts) != 4 || decoded[1].Content.Parts[1].ImageURL.Detail != openai.ImageDetailLow {
+		t.Errorf("Unexpected parts: %+v", decoded[1].Content.Parts)
+	}
+	if decoded[1].Content.String() != "What is in this image?" {
+		t.Errorf("Content text mismatch. Got %q", decoded[1].Content.String())
+	}
+	if decoded[2].Content.String() != "" || decoded[2].Refusal == "" {
+		t.Errorf("Unexpected refusal message: %+v", decoded[2])
+	}
+
+	var m openai.ChatMessage
+	if err := json.Unmarshal([]byte(`{"role": "user", "content": 42}`), &m); err == nil {
+		t.Error("Expected error for invalid content")
+	}
+}
+
+func TestCreateChatCompletionMessages(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	resp, err := fs.Client().Chat().CreateChatCompletion(&openai.ChatCompletionRequest{
+		Model: "gpt-4o",
+		Messages: []openai.ChatMessage{
+			openai.DeveloperMessage("Answer in French."),
+			openai.UserMessage("Hello"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletion error")
+	}
+	if resp.Choices[0].Message.Role != openai.ChatRoleAssistant || resp.Choices[0].Message.Content.String() == "" {
+		t.Errorf("Unexpected message: %+v", resp.Choices[0].Message)
+	}
+	var sent struct {
+		Messages []map[string]any `json:"messages"`
+	}
+	if err := fs.LastCall(t, "POST", "/v1/chat/completions").JSON(&sent); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent.Messages) != 2 || sent.Messages[0]["role"] != "developer" || sent.Messages[1]["content"] != "Hello" {
+		t.Errorf("Unexpected messages sent: %v", sent.Messages)
+	}
+}
@@ -0,0 +1,342 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
+const defaultStreamIdleTimeout = 2 * time.Minute
+
+// ErrStreamClosed is returned when reading a stream after Close.
+var ErrStreamClosed = errors.New("openai: stream closed")
+
+// ChatCompletionStreamOptions - options for streaming responses.
+type ChatCompletionStreamOptions struct {
+	// If set, an additional chunk is streamed before the data: [DONE] message, with the token usage
+	// of the whole request in its Usage field and an empty Choices list.
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// ChatCompletionChunk - a streamed chunk of a chat completion response.
+type ChatCompletionChunk struct {
+	Id      string `json:"id"`
+	Object  string `json:"object"`
+	Created int    `json:"created"`
+	// The model used for the chat completion.
+	Model string `json:"model"`
+	// Each chunk has the same fingerprint.
+	SystemFingerprint string                      `json:"system_fingerprint,omitempty"`
+	Choices           []ChatCompletionChunkChoice `json:"choices"`
+	// Token usage of the whole request, only set on the last chunk when stream_options.include_usage is set.
+	Usage *ChatCompletionUsage `json:"usage,omitempty"`
+	// Azure OpenAI content filtering results for the prompt, sent in a first chunk without an ID or choices.
+	PromptFilterResults []PromptFilterResult `json:"prompt_filter_results,omitempty"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// ChatCompletionChunkChoice - the delta of a choice in a streamed chunk.
+type ChatCompletionChunkChoice struct {
+	Index int              `json:"index"`
+	Delta ChatMessageDelta `json:"delta"`
+	// Set on the last chunk of the choice.
+	FinishReason string `json:"finish_reason,omitempty"`
+	// Log probabilities of the tokens of the chunk, when requested.
+	Logprobs *ChatLogprobs `json:"logprobs,omitempty"`
+	// Azure OpenAI content filtering results for the choice.
+	ContentFilterResults *ContentFilterResults `json:"content_filter_results,omitempty"`
+}
+
+// ChatMessageDelta - the part of a message generated since the previous chunk.
+type ChatMessageDelta struct {
+	// Only set on the first chunk of a choice.
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+	Refusal string `json:"refusal,omitempty"`
+	// Fragments of the tool calls, identified by their index.
+	ToolCalls []ChatToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ChatToolCallDelta - a fragment of a tool call. The first fragment of a call carries its ID, type and
+// function name; the function arguments are split across the following ones.
+type ChatToolCallDelta struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function"`
+}
+
+// RawJSON returns the JSON the ChatCompletionChunk was decoded from.
+func (c *ChatCompletionChunk) RawJSON() json.RawMessage {
+	return c.rawJSON
+}
+
+func (c *ChatCompletionChunk) UnmarshalJSON(data []byte) error {
+	type alias ChatCompletionChunk
+	extra, err := unmarshalWithExtra(data, (*alias)(c))
+	c.ExtraFields = extra
+	c.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (c ChatCompletionChunk) MarshalJSON() ([]byte, error) {
+	type alias ChatCompletionChunk
+	return marshalWithExtra(alias(c), c.ExtraFields)
+}
+
+// ChatCompletionStream - a chat completion streamed as server-sent events.
+//
+// Chunks are read with Recv, or with Next and Value:
+//
+//	stream, err := client.Chat().CreateChatCompletionStream(req)
+//	if err != nil {
+//		return err
+//	}
+//	defer stream.Close()
+//	for stream.Next() {
+//		fmt.Print(stream.Value().Choices[0].Delta.Content)
+//	}
+//	if err := stream.Err(); err != nil {
+//		return err
+//	}
+//
+// The stream fails with an error matching ErrTimeout when no data, including keep-alive comments,
+// arrives within the client's StreamIdleTimeout. The client's overall HTTP timeout does not apply,
+// and a per-request option.WithTimeout only bounds the wait for the response headers.
+//
+// Hooks, the rate limiter and the circuit breaker see the call end when the stream completes,
+// fails or is closed, with the token usage of the last chunk when stream_options.include_usage is set.
+type ChatCompletionStream struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	idle   time.Duration
+	timer  *time.Timer
+
+	res    *http.Response
+	events *sseReader
+	chunk  *ChatCompletionChunk
+	err    error
+	once   sync.Once
+
+	// end finishes the call of the stream, see rawResponse.end.
+	end  func(result any, err error)
+	mu   sync.Mutex
+	last *ChatCompletionChunk
+}
+
+// streamKey marks the context of a streaming request, which is not bounded by the HTTP client timeout.
+type streamKey struct{}
+
+func newChatCompletionStream(ctx context.Context, idle time.Duration) *ChatCompletionStream {
+	ctx, cancel := context.WithCancelCause(context.WithValue(ctx, streamKey{}, true))
+	s := &ChatCompletionStream{ctx: ctx, cancel: cancel, idle: idle}
+	if idle > 0 {
+		s.timer = time.AfterFunc(idle, func() {
+			s.cancel(fmt.Errorf("%w: no stream data received for %s", ErrTimeout, idle))
+		})
+	}
+	return s
+}
+
+// touch restarts the idle timer.
+func (s *ChatCompletionStream) touch() {
+	if s.timer != nil {
+		s.timer.Reset(s.idle)
+	}
+}
+
+// cause returns the reason the stream's context was cancelled, or err if it was not.
+func (s *ChatCompletionStream) cause(err error) error {
+	if cause := context.Cause(s.ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return err
+}
+
+// Recv returns the next chunk of the stream, or io.EOF once the stream is complete.
+func (s *ChatCompletionStream) Recv() (*ChatCompletionChunk, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	chunk, err := s.recv()
+	if err != nil {
+		s.err = err
+		s.finish(err)
+		s.Close()
+		return nil, err
+	}
+	s.mu.Lock()
+	s.last = chunk
+	s.mu.Unlock()
+	return chunk, nil
+}
+
+// finish ends the call of the stream with err, reporting the last chunk received to hooks
+// as it carries the token usage when requested. Only the first call has effect.
+func (s *ChatCompletionStream) finish(err error) {
+	if s.end == nil {
+		return
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+	if last == nil {
+		s.end(nil, err)
+		return
+	}
+	s.end(last, err)
+}
+
+func (s *ChatCompletionStream) recv() (*ChatCompletionChunk, error) {
+	ev, err := s.events.next()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, s.cause(err)
+	}
+	if string(ev.data) == "[DONE]" {
+		return nil, io.EOF
+	}
+	if err := s.eventError(ev); err != nil {
+		return nil, err
+	}
+	var chunk ChatCompletionChunk
+	if err := json.Unmarshal(ev.data, &chunk); err != nil {
+		snippet := &snippetBuffer{}
+		snippet.Write(ev.data)
+		return nil, &DecodeError{
+			HTTPStatusCode: s.res.StatusCode,
+			ContentType:    s.res.Header.Get("Content-Type"),
+			Body:           snippet.Bytes(),
+			Target:         "*openai.ChatCompletionChunk",
+			Err:            err,
+		}
+	}
+	return &chunk, nil
+}
+
+// eventError returns the error carried by an error event, or a data event with an error object.
+func (s *ChatCompletionStream) eventError(ev *sseEvent) error {
+	var resp ErrorResponse
+	if err := json.Unmarshal(ev.data, &resp); err != nil || resp.Error == nil {
+		if ev.event != "error" {
+			return nil
+		}
+		resp.Error = &APIError{}
+		if err := json.Unmarshal(ev.data, resp.Error); err != nil {
+			resp.Error.Message = string(ev.data)
+		}
+	}
+	resp.Error.RequestID = s.res.Header.Get("x-request-id")
+	return resp.Error
+}
+
+// Next advances the stream to the next chunk, which is then available through Value.
+// It returns false when the stream is complete or fails; Err reports the failure.
+func (s *ChatCompletionStream) Next() bool {
+	s.chunk, _ = s.Recv()
+	return s.chunk != nil
+}
+
+// Value returns the current chunk.
+func (s *ChatCompletionStream) Value() *ChatCompletionChunk {
+	return s.chunk
+}
+
+// Err returns the error that stopped the stream, if any. It is nil once the stream completed.
+func (s *ChatCompletionStream) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// Close releases the connection. It may be called at any time, including from another goroutine
+// to abort a Recv in progress, and is safe to call more than once.
+func (s *ChatCompletionStream) Close() error {
+	// A stream closed before it completed ends its call without an error: the caller stopped reading.
+	s.finish(nil)
+	s.cancel(ErrStreamClosed)
+	var err error
+	s.once.Do(func() {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		if s.res != nil {
+			err = s.res.Body.Close()
+		}
+	})
+	return err
+}
+
+// idleReader restarts the idle timer of a stream whenever data arrives.
+type idleReader struct {
+	r     io.Reader
+	touch func()
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.touch()
+	}
+	return n, err
+}
+
+func (e *ChatEndpoint) streamIdleTimeout() time.Duration {
+	if e.StreamIdleTimeout == 0 {
+		return defaultStreamIdleTimeout
+	}
+	return e.StreamIdleTimeout
+}
+
+// Creates a model response for the given chat conversation, streamed as it is generated.
+// The request is sent with stream set to true. The caller must close the returned stream.
+//
+// [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/chat/create
+func (e *ChatEndpoint) CreateChatCompletionStream(req *ChatCompletionRequest, opts ...option.RequestOption) (*ChatCompletionStream, error) {
+	return e.CreateChatCompletionStreamWithContext(context.Background(), req, opts...)
+}
+
+// CreateChatCompletionStreamWithContext is like CreateChatCompletionStream but uses ctx for the request.
+// Cancelling ctx aborts the stream.
+func (e *ChatEndpoint) CreateChatCompletionStreamWithContext(ctx context.Context, req *ChatCompletionRequest, opts ...option.RequestOption) (*ChatCompletionStream, error) {
+	s := newChatCompletionStream(ctx, e.streamIdleTimeout())
+	opts = append([]option.RequestOption{option.WithHeader("Accept", "text/event-stream")}, opts...)
+	raw, err := runWithFallback(s.ctx, e.fallback, req.Model, opts, func(ctx context.Context, model string, opts []option.RequestOption) (*rawResponse, error) {
+		s.touch()
+		r := *req
+		r.Model = model
+		r.Stream = true
+		var raw rawResponse
+		err := e.do(ctx, e, "POST", "completions", &r, nil, &raw, opts...)
+		return &raw, err
+	})
+	if err != nil {
+		err = s.cause(err)
+		s.Close()
+		return nil, err
+	}
+	s.res = raw.res
+	s.end = raw.end
+	s.events = newSSEReader(&idleReader{r: raw.res.Body, touch: s.touch})
+	return s, nil
+}
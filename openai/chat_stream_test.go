@@ -0,0 +1,247 @@
+package openai_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestCreateChatCompletionStream(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	stream, err := fs.Client().Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{
+		Model:         "gpt-4o",
+		Messages:      []openai.ChatMessage{openai.UserMessage("Hello")},
+		StreamOptions: &openai.ChatCompletionStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	var usage *openai.ChatCompletionUsage
+	for stream.Next() {
+		chunk := stream.Value()
+		for _, c := range chunk.Choices {
+			content.WriteString(c.Delta.Content)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Error(err, "Stream error")
+	}
+	if content.String() != "This is a fake response." {
+		t.Errorf("Content mismatch. Got %q", content.String())
+	}
+	if usage == nil || usage.CompletionTokens != 6 {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Unexpected error after the end of the stream: %v", err)
+	}
+
+	call := fs.LastCall(t, "POST", "/v1/chat/completions")
+	if call.Header.Get("Accept") != "text/event-stream" {
+		t.Errorf("Accept header mismatch. Got %q", call.Header.Get("Accept"))
+	}
+	var sent map[string]any
+	if err := call.JSON(&sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent["stream"] != true {
+		t.Errorf("Request not streamed: %v", sent)
+	}
+}
+
+func TestCreateChatCompletionRejectsStream(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	_, err := fs.Client().Chat().CreateChatCompletion(&openai.ChatCompletionRequest{Model: "gpt-4o", Stream: true})
+	if err == nil {
+		t.Error("Expected error")
+	}
+	fs.AssertCalled(t, "POST", "/v1/chat/completions", 0)
+}
+
+func streamServer(t *testing.T, handler func(w http.ResponseWriter, flush func())) *openai_test.TestServer {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("x-request-id", "req_123")
+		handler(w, w.(http.Flusher).Flush)
+	})
+	ts.HTTPServer.Start()
+	t.Cleanup(ts.HTTPServer.Close)
+	return ts
+}
+
+const testChunk = `{"id":"chatcmpl-1","object":"chat.completion.chunk","model":"gpt-4o","choices":[{"index":0,"delta":{"content":"Hi"}}]}`
+
+func TestChatCompletionStreamEvents(t *testing.T) {
+	ts := streamServer(t, func(w http.ResponseWriter, flush func()) {
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprintf(w, "data: %s\r\n\r\n", testChunk)
+		fmt.Fprint(w, "event: error\ndata: {\"message\": \"The server had an error\", \"type\": \"server_error\"}\n\n")
+	})
+	stream, err := openai_test.NewTestClient(ts).Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+	chunk, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err, "Recv error")
+	}
+	if chunk.Choices[0].Delta.Content != "Hi" {
+		t.Errorf("Unexpected chunk: %+v", chunk)
+	}
+	_, err = stream.Recv()
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != "server_error" || apiErr.RequestID != "req_123" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if stream.Next() || stream.Err() != err {
+		t.Errorf("Stream error not sticky: %v", stream.Err())
+	}
+}
+
+func TestChatCompletionStreamDataError(t *testing.T) {
+	ts := streamServer(t, func(w http.ResponseWriter, flush func()) {
+		fmt.Fprint(w, "data: {\"error\": {\"message\": \"overloaded\", \"type\": \"server_error\"}}\n\n")
+	})
+	stream, err := openai_test.NewTestClient(ts).Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+	var apiErr *openai.APIError
+	if _, err := stream.Recv(); !errors.As(err, &apiErr) || apiErr.Message != "overloaded" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestChatCompletionStreamUnexpectedEOF(t *testing.T) {
+	ts := streamServer(t, func(w http.ResponseWriter, flush func()) {
+		fmt.Fprintf(w, "data: %s\n\n", testChunk)
+	})
+	stream, err := openai_test.NewTestClient(ts).Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+	for stream.Next() {
+	}
+	if !errors.Is(stream.Err(), io.ErrUnexpectedEOF) {
+		t.Errorf("Unexpected error: %v, expected: %v", stream.Err(), io.ErrUnexpectedEOF)
+	}
+}
+
+func TestChatCompletionStreamIdleTimeout(t *testing.T) {
+	// Keep-alives hold the stream open past the HTTP client timeout.
+	ts := streamServer(t, func(w http.ResponseWriter, flush func()) {
+		for i := 0; i < 5; i++ {
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", testChunk)
+	})
+	client := openai_test.NewTestClient(ts, openai.WithTimeout(50*time.Millisecond), openai.WithStreamIdleTimeout(50*time.Millisecond))
+	stream, err := client.Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	for stream.Next() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Error(err, "Stream error")
+	}
+
+	// A stalled stream fails once idle.
+	release := make(chan struct{})
+	defer close(release)
+	ts = streamServer(t, func(w http.ResponseWriter, flush func()) {
+		fmt.Fprintf(w, "data: %s\n\n", testChunk)
+		flush()
+		<-release
+	})
+	client = openai_test.NewTestClient(ts, openai.WithStreamIdleTimeout(50*time.Millisecond))
+	stream, err = client.Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err, "Recv error")
+	}
+	if _, err := stream.Recv(); !errors.Is(err, openai.ErrTimeout) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrTimeout)
+	}
+}
+
+func TestChatCompletionStreamRequestTimeout(t *testing.T) {
+	// The per-request timeout bounds the response headers, not the whole stream.
+	ts := streamServer(t, func(w http.ResponseWriter, flush func()) {
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(w, "data: %s\n\n", testChunk)
+			flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	client := openai_test.NewTestClient(ts)
+	stream, err := client.Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"}, option.WithTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	chunks := 0
+	for stream.Next() {
+		chunks++
+	}
+	if err := stream.Err(); err != nil || chunks != 5 {
+		t.Errorf("Unexpected stream end after %d chunks: %v", chunks, err)
+	}
+
+	// Late response headers fail the request.
+	release := make(chan struct{})
+	defer close(release)
+	ts = openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/chat/completions", func(http.ResponseWriter, *http.Request) {
+		<-release
+	})
+	ts.HTTPServer.Start()
+	t.Cleanup(ts.HTTPServer.Close)
+	client = openai_test.NewTestClient(ts)
+	_, err = client.Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"}, option.WithTimeout(50*time.Millisecond))
+	if !errors.Is(err, openai.ErrTimeout) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrTimeout)
+	}
+}
+
+func TestChatCompletionStreamClose(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	ts := streamServer(t, func(w http.ResponseWriter, flush func()) {
+		flush()
+		<-release
+	})
+	stream, err := openai_test.NewTestClient(ts).Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	time.AfterFunc(20*time.Millisecond, func() { stream.Close() })
+	if _, err := stream.Recv(); !errors.Is(err, openai.ErrStreamClosed) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrStreamClosed)
+	}
+}
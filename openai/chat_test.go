@@ -25,10 +25,11 @@ func TestCreateChatCompletion(t *testing.T) {
 	client := openai_test.NewTestClient(ts)
 
 	req := openai.ChatCompletionRequest{
-		Model:    testModelID,
-		Messages: []string{"test"},
-		Role:     "user",
-		Content:  "What is the capital of France?",
+		Model: testModelID,
+		Messages: []openai.ChatMessage{
+			openai.SystemMessage("You are a helpful assistant."),
+			openai.UserMessage("What is the capital of France?"),
+		},
 	}
 	_, err := client.Chat().CreateChatCompletion(&req)
 	t.Helper()
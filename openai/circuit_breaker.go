@@ -0,0 +1,289 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the API while a CircuitBreaker is open.
+var ErrCircuitOpen = errors.New("openai: circuit breaker open")
+
+// CircuitState - the state of a circuit.
+type CircuitState int
+
+const (
+	// Requests flow normally and failures are counted.
+	CircuitClosed CircuitState = iota
+	// Requests fail fast with ErrCircuitOpen.
+	CircuitOpen
+	// A limited number of probe requests are let through to test whether the upstream has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreaker - stops sending requests to an upstream which is failing.
+//
+// Circuits are scoped per API base URL, including its path, and per model too when PerModel is set. A closed circuit
+// opens once at least MinRequests calls were made within Window and the ratio of failures
+// (server errors, timeouts and network errors by default) reaches FailureRatio. After OpenTimeout
+// the circuit half-opens and lets HalfOpenProbes requests through: if they all succeed the
+// circuit closes, otherwise it opens again. Failures are counted after retries.
+type CircuitBreaker struct {
+	// Failure ratio which opens the circuit, between 0 and 1. Defaults to 0.5.
+	FailureRatio float64
+	// Minimum number of calls within Window before the circuit can open. Defaults to 10.
+	MinRequests int
+	// Period over which calls are counted. Defaults to one minute.
+	Window time.Duration
+	// How long the circuit stays open before half-opening. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+	// Number of probe requests let through while half-open. Defaults to 1.
+	HalfOpenProbes int
+	// Scope circuits per model as well as per base URL.
+	PerModel bool
+	// Reports whether an error counts as an upstream failure. Defaults to IsCircuitFailure.
+	IsFailure func(error) bool
+	// Called when a circuit changes state. It must not call the breaker.
+	OnStateChange func(key string, from CircuitState, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker with the default thresholds.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{}
+}
+
+// WithCircuitBreaker adds a circuit breaker to the client's request path.
+func WithCircuitBreaker(cb *CircuitBreaker) ClientOption {
+	return func(c *Client) error {
+		c.CircuitBreaker = cb
+		return nil
+	}
+}
+
+// IsCircuitFailure reports whether err indicates an unhealthy upstream:
+// a server error, a timeout or a network error. Client errors such as
+// invalid requests and rate limits do not count.
+func IsCircuitFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, ErrServerError) || errors.Is(err, ErrTimeout) || IsRetryableNetworkError(err)
+}
+
+type circuit struct {
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+func (cb *CircuitBreaker) clock() time.Time {
+	if cb.now != nil {
+		return cb.now()
+	}
+	return time.Now()
+}
+
+func (cb *CircuitBreaker) failureRatio() float64 {
+	if cb.FailureRatio <= 0 {
+		return 0.5
+	}
+	return cb.FailureRatio
+}
+
+func (cb *CircuitBreaker) minRequests() int {
+	if cb.MinRequests <= 0 {
+		return 10
+	}
+	return cb.MinRequests
+}
+
+func (cb *CircuitBreaker) window() time.Duration {
+	if cb.Window <= 0 {
+		return time.Minute
+	}
+	return cb.Window
+}
+
+func (cb *CircuitBreaker) openTimeout() time.Duration {
+	if cb.OpenTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return cb.OpenTimeout
+}
+
+func (cb *CircuitBreaker) halfOpenProbes() int {
+	if cb.HalfOpenProbes <= 0 {
+		return 1
+	}
+	return cb.HalfOpenProbes
+}
+
+// circuitKey returns the key of the circuit for requests to the API at base targeting model.
+func (cb *CircuitBreaker) circuitKey(base *url.URL, model string) string {
+	key := base.Scheme + "://" + base.Host + strings.TrimSuffix(base.Path, "/")
+	if cb.PerModel && model != "" {
+		key += " " + model
+	}
+	return key
+}
+
+// setState moves c to state. The caller must hold cb.mu.
+func (cb *CircuitBreaker) setState(key string, c *circuit, state CircuitState, now time.Time) {
+	from := c.state
+	*c = circuit{state: state, windowStart: now}
+	if state == CircuitOpen {
+		c.openedAt = now
+	}
+	if cb.OnStateChange != nil && from != state {
+		cb.OnStateChange(key, from, state)
+	}
+}
+
+// circuit returns the circuit for key, half-opening it if its open timeout has passed.
+// The caller must hold cb.mu.
+func (cb *CircuitBreaker) circuit(key string, now time.Time) *circuit {
+	if cb.circuits == nil {
+		cb.circuits = make(map[string]*circuit)
+	}
+	c, ok := cb.circuits[key]
+	if !ok {
+		c = &circuit{windowStart: now}
+		cb.circuits[key] = c
+	}
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= cb.openTimeout() {
+		cb.setState(key, c, CircuitHalfOpen, now)
+	}
+	return c
+}
+
+// allow reports whether a request may be sent on the circuit for key.
+// On success it returns a function which must be called with the outcome of the request,
+// and whether it was sent: requests which fail before reaching the API are not recorded.
+func (cb *CircuitBreaker) allow(key string) (func(err error, sent bool), error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := cb.clock()
+	c := cb.circuit(key, now)
+	switch c.state {
+	case CircuitOpen:
+		return nil, fmt.Errorf("%w: %s, retry in %s", ErrCircuitOpen, key, cb.openTimeout()-now.Sub(c.openedAt))
+	case CircuitHalfOpen:
+		if c.probes >= cb.halfOpenProbes() {
+			return nil, fmt.Errorf("%w: %s, probing", ErrCircuitOpen, key)
+		}
+		c.probes++
+	}
+	state := c.state
+	return func(err error, sent bool) {
+		// Client-side failures and callers giving up say nothing about the upstream.
+		if !sent || errors.Is(err, context.Canceled) {
+			cb.release(key, state)
+			return
+		}
+		cb.record(key, state, err)
+	}, nil
+}
+
+// release gives back the probe taken by a request allowed while the circuit for key was in state.
+func (cb *CircuitBreaker) release(key string, state CircuitState) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if c := cb.circuits[key]; c != nil && state == CircuitHalfOpen && c.state == CircuitHalfOpen {
+		c.probes--
+	}
+}
+
+// record updates the circuit for key with the outcome of a request sent while it was in state.
+func (cb *CircuitBreaker) record(key string, state CircuitState, err error) {
+	isFailure := cb.IsFailure
+	if isFailure == nil {
+		isFailure = IsCircuitFailure
+	}
+	failed := isFailure(err)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := cb.clock()
+	c := cb.circuit(key, now)
+	if c.state != state {
+		// The circuit changed state while the request was in flight.
+		return
+	}
+	switch c.state {
+	case CircuitHalfOpen:
+		if failed {
+			cb.setState(key, c, CircuitOpen, now)
+			return
+		}
+		c.successes++
+		if c.successes >= cb.halfOpenProbes() {
+			cb.setState(key, c, CircuitClosed, now)
+		}
+	case CircuitClosed:
+		if now.Sub(c.windowStart) >= cb.window() {
+			c.windowStart = now
+			c.requests = 0
+			c.failures = 0
+		}
+		c.requests++
+		if failed {
+			c.failures++
+		}
+		if c.requests >= cb.minRequests() && float64(c.failures)/float64(c.requests) >= cb.failureRatio() {
+			cb.setState(key, c, CircuitOpen, now)
+		}
+	}
+}
+
+// State returns the state of the circuit for the API at baseURL, e.g. https://api.openai.com,
+// and model when the breaker is scoped per model.
+func (cb *CircuitBreaker) State(baseURL string, model string) CircuitState {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return CircuitClosed
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	key := cb.circuitKey(u, model)
+	if _, ok := cb.circuits[key]; !ok {
+		return CircuitClosed
+	}
+	return cb.circuit(key, cb.clock()).state
+}
+
+// States returns the state of every circuit by key, for health checks.
+// Keys are the base URL, followed by a space and the model when the breaker is scoped per model.
+func (cb *CircuitBreaker) States() map[string]CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := cb.clock()
+	states := make(map[string]CircuitState, len(cb.circuits))
+	for key := range cb.circuits {
+		states[key] = cb.circuit(key, now).state
+	}
+	return states
+}
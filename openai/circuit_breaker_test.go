@@ -0,0 +1,149 @@
+package openai_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	cb := &openai.CircuitBreaker{MinRequests: 4, FailureRatio: 0.5, OpenTimeout: 50 * time.Millisecond}
+	var transitions []string
+	cb.OnStateChange = func(_ string, from, to openai.CircuitState) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+	}
+	client := fs.Client(openai.WithCircuitBreaker(cb))
+
+	// Client errors do not count as failures.
+	for i := 0; i < 4; i++ {
+		if _, err := client.Models().RetrieveModel("missing"); !errors.Is(err, openai.ErrNotFound) {
+			t.Fatalf("Unexpected error: %v, expected: %v", err, openai.ErrNotFound)
+		}
+	}
+	if state := cb.State(fs.HTTPServer.URL, ""); state != openai.CircuitClosed {
+		t.Fatalf("Unexpected state: %s", state)
+	}
+
+	fs.InjectFault(openai_test.Fault{StatusCode: http.StatusServiceUnavailable})
+	for i := 0; i < 4; i++ {
+		if _, err := client.Models().ListModels(); !errors.Is(err, openai.ErrServerError) {
+			t.Fatalf("Unexpected error: %v, expected: %v", err, openai.ErrServerError)
+		}
+	}
+	if state := cb.State(fs.HTTPServer.URL, ""); state != openai.CircuitOpen {
+		t.Fatalf("Unexpected state: %s", state)
+	}
+	calls := len(fs.Calls())
+	if _, err := client.Models().ListModels(); !errors.Is(err, openai.ErrCircuitOpen) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrCircuitOpen)
+	}
+	if len(fs.Calls()) != calls {
+		t.Error("Request sent while the circuit was open")
+	}
+
+	// A failed probe opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Models().ListModels(); !errors.Is(err, openai.ErrServerError) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrServerError)
+	}
+	if state := cb.State(fs.HTTPServer.URL, ""); state != openai.CircuitOpen {
+		t.Errorf("Unexpected state: %s", state)
+	}
+
+	// A successful probe closes it.
+	fs.ClearFaults()
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Models().ListModels(); err != nil {
+		t.Error(err, "ListModels error")
+	}
+	states := cb.States()
+	if len(states) != 1 || states[fs.HTTPServer.URL] != openai.CircuitClosed {
+		t.Errorf("Unexpected states: %v", states)
+	}
+	want := "[closed->open open->half-open half-open->open open->half-open half-open->closed]"
+	if got := fmt.Sprint(transitions); got != want {
+		t.Errorf("Transitions mismatch. Got %s. Expected %s", got, want)
+	}
+}
+
+func TestCircuitBreakerClientSideError(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	cb := &openai.CircuitBreaker{MinRequests: 2, OpenTimeout: 50 * time.Millisecond}
+	client := fs.Client(openai.WithCircuitBreaker(cb))
+	client.RateLimiter = openai.NewRateLimiter(map[string]openai.ModelLimit{"limited": {RequestsPerMinute: 2}})
+	client.RateLimiter.FailFast = true
+
+	fs.InjectFault(openai_test.Fault{StatusCode: http.StatusServiceUnavailable})
+	for i := 0; i < 2; i++ {
+		client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "limited", Input: "test"})
+	}
+	if state := cb.State(fs.HTTPServer.URL, ""); state != openai.CircuitOpen {
+		t.Fatalf("Unexpected state: %s", state)
+	}
+
+	// A probe which fails before it is sent neither closes the circuit nor uses up the probe.
+	fs.ClearFaults()
+	time.Sleep(60 * time.Millisecond)
+	_, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "limited", Input: "test"})
+	if !errors.Is(err, openai.ErrRateLimiterExhausted) {
+		t.Fatalf("Unexpected error: %v, expected: %v", err, openai.ErrRateLimiterExhausted)
+	}
+	if state := cb.State(fs.HTTPServer.URL, ""); state != openai.CircuitHalfOpen {
+		t.Errorf("Unexpected state: %s", state)
+	}
+	if _, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "other", Input: "test"}); err != nil {
+		t.Error(err, "CreateEmbeddings error")
+	}
+	if state := cb.State(fs.HTTPServer.URL, ""); state != openai.CircuitClosed {
+		t.Errorf("Unexpected state: %s", state)
+	}
+}
+
+func TestCircuitBreakerBasePath(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	cb := &openai.CircuitBreaker{MinRequests: 1}
+	client := fs.Client(openai.WithCircuitBreaker(cb))
+
+	// Requests to another API root on the same host use their own circuit.
+	_, err := client.Models().ListModels(option.WithBaseURL(fs.HTTPServer.URL + "/gateway"))
+	if !errors.Is(err, openai.ErrNotFound) {
+		t.Fatalf("Unexpected error: %v, expected: %v", err, openai.ErrNotFound)
+	}
+	states := cb.States()
+	if _, ok := states[fs.HTTPServer.URL+"/gateway"]; !ok || len(states) != 1 {
+		t.Errorf("Unexpected states: %v", states)
+	}
+}
+
+func TestCircuitBreakerPerModel(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	fs.ScriptFunc("/v1/embeddings", func(req map[string]any) (any, error) {
+		if req["model"] == "broken" {
+			return nil, &openai.APIError{Message: "overloaded", HTTPStatusCode: http.StatusInternalServerError}
+		}
+		return map[string]any{"object": "list", "model": req["model"]}, nil
+	})
+	cb := &openai.CircuitBreaker{MinRequests: 2, PerModel: true, OpenTimeout: time.Minute}
+	client := fs.Client(openai.WithCircuitBreaker(cb))
+
+	for i := 0; i < 3; i++ {
+		client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "broken", Input: "test"})
+	}
+	if state := cb.State(fs.HTTPServer.URL, "broken"); state != openai.CircuitOpen {
+		t.Errorf("Unexpected state: %s", state)
+	}
+	if _, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "healthy", Input: "test"}); err != nil {
+		t.Error(err, "CreateEmbeddings error")
+	}
+}
@@ -2,17 +2,26 @@ package openai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
 )
 
 const (
-	apiURL = "https://api.openai.com"
+	apiURL         = "https://api.openai.com"
+	defaultTimeout = 30 * time.Second
 )
 
 // Client - OpenAI client.
@@ -21,102 +30,513 @@ type Client struct {
 
 	BaseURL        *url.URL
 	OrganizationID string
-	HTTPClient     *http.Client
-	UserAgent      string
+	// Project ID sent in the OpenAI-Project header.
+	ProjectID  string
+	HTTPClient *http.Client
+	UserAgent  string
+	// Headers added to every request.
+	DefaultHeaders http.Header
+	// Beta features sent in the OpenAI-Beta header by beta endpoints.
+	BetaFeatures []string
+	// Retry policy applied to every request. A nil policy disables retries.
+	RetryPolicy *RetryPolicy
+	// Client-side rate limiter applied to model requests. A nil limiter disables throttling.
+	RateLimiter *RateLimiter
+	// Middleware wrapped around every outbound request. See Use.
+	Middleware []Middleware
+	// Azure OpenAI configuration. A nil config targets the OpenAI API.
+	Azure *AzureConfig
+	// Instrumentation hooks called around every API call.
+	Hooks []Hook
+	// Cache for the responses of deterministic calls. A nil cache disables caching.
+	Cache Cache
+	// How long cached responses are kept. Zero keeps them until evicted.
+	CacheTTL time.Duration
+	// Circuit breaker which fails fast while the API is unhealthy. A nil breaker is disabled.
+	CircuitBreaker *CircuitBreaker
+	// Maximum time to wait for data on a stream, which replaces the HTTP client timeout for streams.
+	// Zero defaults to two minutes and a negative value waits indefinitely.
+	StreamIdleTimeout time.Duration
 }
 
-// NewClient creates new OpenAI client.
-func NewClient(authToken string) *Client {
+// NewClient creates new OpenAI client configured by opts.
+//
+//	client, err := openai.NewClient(openai.WithAPIKey(key), openai.WithProject("proj_123"))
+func NewClient(opts ...ClientOption) (*Client, error) {
 	c := &Client{
-		HTTPClient: &http.Client{Timeout: 30 * time.Second},
-		authToken:  authToken,
-		UserAgent:  "skyscrapr/openai-sdk-go",
+		HTTPClient:   &http.Client{Timeout: defaultTimeout},
+		UserAgent:    "skyscrapr/openai-sdk-go",
+		BetaFeatures: []string{"assistants=v2"},
 	}
 	c.BaseURL, _ = url.Parse(apiURL)
-	return c
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
-func (c *Client) do(e endpointI, method string, path string, body interface{}, values url.Values, result interface{}) error {
-	u, err := e.buildURL(path)
+func (c *Client) do(ctx context.Context, e endpointI, method string, path string, body interface{}, values url.Values, result interface{}, opts ...option.RequestOption) (err error) {
+	call := &CallInfo{
+		Method:   method,
+		Endpoint: e.endpointName(),
+		Model:    requestModel(body),
+		Request:  body,
+	}
+	cfg, err := option.NewRequestConfig(opts...)
+	if err != nil {
+		return err
+	}
+	u, err := e.buildURL(path, call.Model)
 	if err != nil {
 		return err
 	}
-	req, err := e.newRequest(method, u, body)
+	if cfg.BaseURL != nil {
+		u = c.rebaseURL(u, cfg.BaseURL)
+	}
+	call.Path = u.Path
+	sendBody := body
+	if len(cfg.ExtraBody) > 0 {
+		if sendBody, err = mergeExtraBody(body, cfg.ExtraBody); err != nil {
+			return err
+		}
+	}
+	if cfg.Timeout > 0 {
+		ctx = context.WithValue(ctx, requestTimeoutKey{}, cfg.Timeout)
+	}
+	var meta *ResponseMeta
+	ctx, finish := c.startCall(ctx, call)
+	// ends are run in reverse order once the call is over. Raw responses are read by the
+	// caller, so their call only ends when rawResponse.end is called.
+	ends := []func(result any, err error){func(result any, err error) { finish(result, meta, err) }}
+	end := func(result any, err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](result, err)
+		}
+	}
+	streamed := false
+	defer func() {
+		if !streamed {
+			end(result, err)
+		}
+	}()
+
+	var key string
+	if c.Cache != nil && !cfg.CacheBypass && cacheable(e, method, path, body, result) {
+		if key, err = c.cacheKey(method, u.String(), sendBody, cfg.Header); err != nil {
+			return err
+		}
+		// The cache is best effort: lookup failures and undecodable entries count as misses.
+		if data, ok, _ := c.Cache.Get(ctx, key); ok && json.Unmarshal(data, result) == nil {
+			meta = cachedResponseMeta()
+			captureResponseMeta(ctx, meta)
+			return nil
+		}
+	}
+
+	sent := false
+	if c.CircuitBreaker != nil {
+		base := c.BaseURL
+		if cfg.BaseURL != nil {
+			base = cfg.BaseURL
+		}
+		var done func(error, bool)
+		if done, err = c.CircuitBreaker.allow(c.CircuitBreaker.circuitKey(base, call.Model)); err != nil {
+			return err
+		}
+		ends = append(ends, func(_ any, err error) { done(err, sent) })
+	}
+
+	var reservation *rateReservation
+	if c.RateLimiter != nil {
+		reservation, err = c.RateLimiter.reserve(ctx, body)
+		if err != nil {
+			return err
+		}
+	}
+	if reservation != nil {
+		ends = append(ends, func(result any, _ error) { c.RateLimiter.reconcile(reservation, meta, result) })
+	}
+	req, err := e.newRequest(ctx, method, u, sendBody)
 	if err != nil {
 		return err
 	}
-	if values != nil {
-		req.URL.RawQuery = values.Encode()
+	for k, v := range cfg.Header {
+		req.Header[k] = append([]string(nil), v...)
 	}
-	return e.doRequest(req, result)
+	if values != nil || cfg.Query != nil {
+		q := req.URL.Query()
+		for k, v := range values {
+			q[k] = v
+		}
+		for k, v := range cfg.Query {
+			q[k] = v
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+	sent = true
+	meta, err = e.doRequest(req, result)
+	if raw, ok := result.(*rawResponse); ok && err == nil {
+		streamed = true
+		var once sync.Once
+		raw.end = func(result any, err error) {
+			once.Do(func() { end(result, err) })
+		}
+	}
+	if err == nil && key != "" {
+		if data, merr := json.Marshal(result); merr == nil {
+			_ = c.Cache.Set(ctx, key, data, c.CacheTTL)
+		}
+	}
+	return err
+}
+
+// rebaseURL moves u, built against the client's base URL, onto base.
+func (c *Client) rebaseURL(u *url.URL, base *url.URL) *url.URL {
+	rebased := *u
+	rebased.Scheme = base.Scheme
+	rebased.Host = base.Host
+	rebased.User = base.User
+	rebased.Path = path.Join("/", base.Path, strings.TrimPrefix(u.Path, c.BaseURL.Path))
+	rebased.RawPath = ""
+	return &rebased
+}
+
+// mergeExtraBody returns the JSON encoding of body with extra deep-merged into it.
+func mergeExtraBody(body interface{}, extra map[string]any) (json.RawMessage, error) {
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case *formBody:
+		return nil, errors.New("openai: extra body fields are not supported for multipart requests")
+	case rawBytes:
+		data = b
+	default:
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	fields := map[string]any{}
+	if data != nil {
+		// Numbers are decoded as json.Number so they are re-encoded exactly.
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return nil, fmt.Errorf("openai: extra body fields require a JSON object body: %w", err)
+		}
+	}
+	return json.Marshal(option.MergeFields(fields, extra))
+}
+
+// requestTimeoutKey is the context key of a per-request timeout.
+type requestTimeoutKey struct{}
+
+// httpDo sends req with the client's HTTP client, applying any per-request timeout.
+// Streams are not bounded by the client timeout, which would cut them off mid-response,
+// and their per-request timeout only bounds the wait for the response headers.
+func (c *Client) httpDo(req *http.Request) (*http.Response, error) {
+	httpClient := c.HTTPClient
+	timeout, hasTimeout := req.Context().Value(requestTimeoutKey{}).(time.Duration)
+	if req.Context().Value(streamKey{}) != nil {
+		if httpClient.Timeout > 0 {
+			copied := *httpClient
+			copied.Timeout = 0
+			httpClient = &copied
+		}
+		if hasTimeout {
+			return doWithHeaderTimeout(httpClient, req, timeout)
+		}
+	} else if hasTimeout {
+		copied := *httpClient
+		copied.Timeout = timeout
+		httpClient = &copied
+	}
+	return httpClient.Do(req)
+}
+
+// headerTimeoutError is returned when the response headers of a stream do not arrive in time.
+type headerTimeoutError struct {
+	timeout time.Duration
 }
 
-func (c *Client) newRequest(method string, u *url.URL, body interface{}) (*http.Request, error) {
-	var buf io.ReadWriter
-	if body != nil {
+func (e *headerTimeoutError) Error() string {
+	return fmt.Sprintf("openai: no response headers received within %s", e.timeout)
+}
+
+func (e *headerTimeoutError) Timeout() bool   { return true }
+func (e *headerTimeoutError) Temporary() bool { return true }
+
+// doWithHeaderTimeout sends req, failing unless the response headers arrive within timeout.
+// Reading the response body is not bounded by timeout.
+func doWithHeaderTimeout(httpClient *http.Client, req *http.Request, timeout time.Duration) (*http.Response, error) {
+	ctx, cancel := context.WithCancelCause(req.Context())
+	timeoutErr := &headerTimeoutError{timeout: timeout}
+	timer := time.AfterFunc(timeout, func() { cancel(timeoutErr) })
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if !timer.Stop() {
+		if err == nil {
+			res.Body.Close()
+		}
+		return nil, timeoutErr
+	}
+	if err != nil {
+		cancel(nil)
+		return nil, err
+	}
+	res.Body = &cancelReadCloser{ReadCloser: res.Body, cancel: func() { cancel(nil) }}
+	return res, nil
+}
+
+// cancelReadCloser releases the context of a response once its body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (r *cancelReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+	return err
+}
+
+// formBody is a pre-encoded multipart/form-data request body.
+type formBody struct {
+	buf         *bytes.Buffer
+	contentType string
+}
+
+func (c *Client) newRequest(ctx context.Context, method string, u *url.URL, body interface{}) (*http.Request, error) {
+	var buf *bytes.Buffer
+	contentType := "application/json; charset=utf-8"
+	switch b := body.(type) {
+	case nil:
+	case *formBody:
+		buf = b.buf
+		contentType = b.contentType
+	case rawBytes:
+		buf = bytes.NewBuffer(b)
+	default:
 		buf = new(bytes.Buffer)
 		err := json.NewEncoder(buf).Encode(body)
 		if err != nil {
 			return nil, err
 		}
 	}
-	req, err := http.NewRequest(method, u.String(), buf)
+	var req *http.Request
+	var err error
+	if buf != nil {
+		req, err = http.NewRequestWithContext(ctx, method, u.String(), buf)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, u.String(), nil)
+	}
 	if err != nil {
 		return nil, err
 	}
-	// req.Header.Set("User-Agent", c.UserAgent)
+	for k, v := range c.DefaultHeaders {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if len(c.UserAgent) > 0 {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.authToken))
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if c.Azure != nil {
+		if err := c.Azure.authorize(req, c.authToken); err != nil {
+			return nil, err
+		}
+	} else if len(c.authToken) > 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.authToken))
+	}
+	if buf != nil {
+		req.Header.Set("Content-Type", contentType)
 	}
 	if len(c.OrganizationID) > 0 {
 		req.Header.Set("OpenAI-Organization", c.OrganizationID)
 	}
+	if len(c.ProjectID) > 0 {
+		req.Header.Set("OpenAI-Project", c.ProjectID)
+	}
 
 	return req, nil
 }
 
-func (c *Client) doRequest(req *http.Request, v any) error {
-	res, err := c.HTTPClient.Do(req)
+func (c *Client) doRequest(req *http.Request, v any) (*ResponseMeta, error) {
+	res, attempts, err := c.send(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	defer res.Body.Close()
+	meta := newResponseMeta(res)
+	meta.Attempts = attempts
+	captureResponseMeta(req.Context(), meta)
 
 	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		return c.handleErrorResp(res)
+		defer res.Body.Close()
+		err = c.handleErrorResp(res)
+		setAttempts(err, attempts)
+		return meta, err
+	}
+	if raw, ok := v.(*rawResponse); ok {
+		// The caller reads and closes the body.
+		raw.res = res
+		return meta, nil
 	}
 
-	return decodeResponse(res.Body, v)
+	defer res.Body.Close()
+	return meta, decodeResponse(res, &contextReader{ctx: req.Context(), r: res.Body}, v)
 }
 
-func decodeResponse(body io.Reader, v any) error {
+// send performs req, retrying it according to c.RetryPolicy.
+// It returns the final response along with the number of attempts made.
+func (c *Client) send(req *http.Request) (*http.Response, int, error) {
+	ctx := req.Context()
+	handler := c.handler()
+	maxAttempts := c.RetryPolicy.maxAttempts()
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body cannot be rewound, so only a single attempt is possible.
+		maxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			var err error
+			r, err = rewindRequest(req)
+			if err != nil {
+				return nil, attempt - 1, &RequestError{Err: err, Attempts: attempt - 1}
+			}
+		}
+		var header http.Header
+		var reused atomic.Bool
+		r = r.WithContext(httptrace.WithClientTrace(r.Context(), &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) { reused.Store(info.Reused) },
+		}))
+		res, err := handler(r)
+		if err == nil && res == nil {
+			err = errors.New("openai: middleware returned neither a response nor an error")
+		}
+		if res != nil && res.Body == nil {
+			res.Body = http.NoBody
+		}
+		if err != nil {
+			// A bare EOF on a fresh connection is a server refusing the request, not a stale keep-alive.
+			staleConn := !errors.Is(err, io.EOF) || reused.Load()
+			if attempt >= maxAttempts || !c.RetryPolicy.retryError(err) || !staleConn {
+				return nil, attempt, &RequestError{Err: err, Attempts: attempt}
+			}
+		} else {
+			if attempt >= maxAttempts || !c.RetryPolicy.retryStatus(res.StatusCode) {
+				return res, attempt, nil
+			}
+			header = res.Header
+		}
+		delay, ok := c.RetryPolicy.delay(attempt, header)
+		if !ok {
+			// The server asked for a longer wait than the policy allows: fail fast with its response.
+			return res, attempt, nil
+		}
+		if res != nil {
+			_, _ = io.CopyN(io.Discard, res.Body, 4096)
+			res.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt, &RequestError{Err: err, Attempts: attempt}
+		}
+	}
+}
+
+// rewindRequest returns a copy of req with a fresh body, ready to be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// contextReader stops reading from r as soon as ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// rawUnmarshaler is implemented by response types which accept non-JSON bodies.
+type rawUnmarshaler interface {
+	unmarshalRaw(contentType string, data []byte) error
+}
+
+// decodeResponse decodes the body of res into v.
+// Bodies decoded into *string, *[]byte or io.Writer are passed through as-is,
+// everything else is decoded as JSON.
+func decodeResponse(res *http.Response, body io.Reader, v any) error {
 	if v == nil {
 		return nil
 	}
-	err := json.NewDecoder(body).Decode(v)
-	if err != nil {
-		log.Fatal(err)
+	contentType := res.Header.Get("Content-Type")
+	var err error
+	snippet := &snippetBuffer{}
+	switch t := v.(type) {
+	case *string:
+		var data []byte
+		data, err = io.ReadAll(body)
+		*t = string(data)
+	case *[]byte:
+		*t, err = io.ReadAll(body)
+	case io.Writer:
+		_, err = io.Copy(t, body)
+	case rawUnmarshaler:
+		var data []byte
+		data, err = io.ReadAll(body)
+		if err == nil {
+			snippet.Write(data)
+			err = t.unmarshalRaw(contentType, data)
+		}
+	default:
+		err = json.NewDecoder(io.TeeReader(body, snippet)).Decode(v)
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return &DecodeError{
+		HTTPStatusCode: res.StatusCode,
+		ContentType:    contentType,
+		Body:           snippet.Bytes(),
+		Target:         fmt.Sprintf("%T", v),
+		Err:            err,
 	}
-	return nil
 }
 
 func (c *Client) handleErrorResp(resp *http.Response) error {
+	requestID := resp.Header.Get("x-request-id")
 	var errRes ErrorResponse
 	err := json.NewDecoder(resp.Body).Decode(&errRes)
 	if err != nil || errRes.Error == nil {
 		reqErr := &RequestError{
 			HTTPStatusCode: resp.StatusCode,
 			Err:            err,
+			RequestID:      requestID,
 		}
 		if errRes.Error != nil {
 			reqErr.Err = errRes.Error
 		}
+		if reqErr.Err == nil {
+			reqErr.Err = errors.New(http.StatusText(resp.StatusCode))
+		}
 		return reqErr
 	}
 
 	errRes.Error.HTTPStatusCode = resp.StatusCode
+	errRes.Error.RequestID = requestID
 	return errRes.Error
 }
@@ -0,0 +1,162 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ErrMissingAPIKey is returned by NewClientFromEnv when OPENAI_API_KEY is not set.
+var ErrMissingAPIKey = errors.New("openai: OPENAI_API_KEY environment variable is not set")
+
+// ClientOption - configures a Client created by NewClient.
+// Options are applied in the order they are given.
+type ClientOption func(c *Client) error
+
+// WithAPIKey sets the API key sent as a bearer token.
+func WithAPIKey(apiKey string) ClientOption {
+	return func(c *Client) error {
+		c.authToken = apiKey
+		return nil
+	}
+}
+
+// WithBaseURL sets the base URL of the API, e.g. https://api.openai.com.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return errors.New("openai: base URL must be absolute: " + baseURL)
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithOrganization sets the organization sent in the OpenAI-Organization header.
+func WithOrganization(organizationID string) ClientOption {
+	return func(c *Client) error {
+		c.OrganizationID = organizationID
+		return nil
+	}
+}
+
+// WithProject sets the project sent in the OpenAI-Project header.
+func WithProject(projectID string) ClientOption {
+	return func(c *Client) error {
+		c.ProjectID = projectID
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("openai: HTTP client must not be nil")
+		}
+		c.HTTPClient = httpClient
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) error {
+		if c.DefaultHeaders == nil {
+			c.DefaultHeaders = http.Header{}
+		}
+		c.DefaultHeaders.Add(key, value)
+		return nil
+	}
+}
+
+// WithTimeout sets the overall timeout of each HTTP request.
+// The HTTP client configured so far is copied rather than modified.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		httpClient := *c.HTTPClient
+		httpClient.Timeout = timeout
+		c.HTTPClient = &httpClient
+		return nil
+	}
+}
+
+// WithStreamIdleTimeout sets the maximum time to wait for data on a stream.
+// A negative timeout waits indefinitely.
+func WithStreamIdleTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.StreamIdleTimeout = timeout
+		return nil
+	}
+}
+
+// WithBeta sets the beta features sent in the OpenAI-Beta header by beta endpoints,
+// replacing the default "assistants=v2".
+func WithBeta(features ...string) ClientOption {
+	return func(c *Client) error {
+		c.BetaFeatures = features
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the retry policy applied to every request.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithRateLimiter sets the client-side rate limiter.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) error {
+		c.RateLimiter = limiter
+		return nil
+	}
+}
+
+// NewClientFromEnv creates a new OpenAI client configured from the environment:
+//
+//	OPENAI_API_KEY     API key (required)
+//	OPENAI_BASE_URL    base URL of the API
+//	OPENAI_ORG_ID      organization ID
+//	OPENAI_PROJECT_ID  project ID
+//
+// opts are applied after the environment and take precedence over it.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+	envOpts := []ClientOption{WithAPIKey(apiKey)}
+	if v := os.Getenv("OPENAI_BASE_URL"); v != "" {
+		envOpts = append(envOpts, func(c *Client) error {
+			if err := WithBaseURL(v)(c); err != nil {
+				return fmt.Errorf("openai: invalid OPENAI_BASE_URL: %w", err)
+			}
+			return nil
+		})
+	}
+	if v := os.Getenv("OPENAI_ORG_ID"); v != "" {
+		envOpts = append(envOpts, WithOrganization(v))
+	}
+	if v := os.Getenv("OPENAI_PROJECT_ID"); v != "" {
+		envOpts = append(envOpts, WithProject(v))
+	}
+	return NewClient(append(envOpts, opts...)...)
+}
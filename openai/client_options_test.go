@@ -0,0 +1,91 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestClientOptionsHeaders(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/assistants/testAssistantId", func(w http.ResponseWriter, r *http.Request) {
+		want := map[string]string{
+			"OpenAI-Organization": "org_123",
+			"OpenAI-Project":      "proj_123",
+			"User-Agent":          "test-agent",
+			"X-Gateway":           "internal",
+			"OpenAI-Beta":         "assistants=v2,realtime=v1",
+		}
+		for k, v := range want {
+			if got := r.Header.Get(k); got != v {
+				t.Errorf("Header %s mismatch. Got %q. Expected %q", k, got, v)
+			}
+		}
+		resBytes, _ := json.Marshal(openai.Assistant{Id: "testAssistantId", Object: "assistant"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts,
+		openai.WithOrganization("org_123"),
+		openai.WithProject("proj_123"),
+		openai.WithUserAgent("test-agent"),
+		openai.WithHeader("X-Gateway", "internal"),
+		openai.WithBeta("assistants=v2", "realtime=v1"),
+	)
+	_, err := client.Assistants().RetrieveAssistant("testAssistantId")
+	if err != nil {
+		t.Error(err, "RetrieveAssistant error")
+	}
+}
+
+func TestClientOptionsTimeout(t *testing.T) {
+	httpClient := &http.Client{}
+	client, err := openai.NewClient(openai.WithHTTPClient(httpClient), openai.WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.HTTPClient.Timeout != time.Minute {
+		t.Errorf("Timeout mismatch. Got %s. Expected %s", client.HTTPClient.Timeout, time.Minute)
+	}
+	if httpClient.Timeout != 0 {
+		t.Error("WithTimeout modified the provided HTTP client")
+	}
+}
+
+func TestClientOptionsInvalidBaseURL(t *testing.T) {
+	_, err := openai.NewClient(openai.WithBaseURL("not a url"))
+	if err == nil {
+		t.Error("Expected an error for an invalid base URL")
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	_, err := openai.NewClientFromEnv()
+	if !errors.Is(err, openai.ErrMissingAPIKey) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrMissingAPIKey)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "testapikey")
+	t.Setenv("OPENAI_BASE_URL", "https://example.com/openai")
+	t.Setenv("OPENAI_ORG_ID", "org_123")
+	t.Setenv("OPENAI_PROJECT_ID", "proj_123")
+	client, err := openai.NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.BaseURL.String() != "https://example.com/openai" {
+		t.Errorf("BaseURL mismatch. Got %s", client.BaseURL)
+	}
+	if client.OrganizationID != "org_123" || client.ProjectID != "proj_123" {
+		t.Errorf("Unexpected client configuration: %s %s", client.OrganizationID, client.ProjectID)
+	}
+}
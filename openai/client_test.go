@@ -0,0 +1,42 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestRequestContextCancelled(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Models().RetrieveModelWithContext(ctx, "testModelID")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestUploadFileContextCancelled(t *testing.T) {
+	client := openai_test.NewTestClient(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Files().UploadFileWithContext(ctx, &openai.UploadFileRequest{
+		File:    "client_test.go",
+		Purpose: "fine-tune",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, context.Canceled)
+	}
+}
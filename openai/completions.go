@@ -1,5 +1,12 @@
 package openai
 
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
 const CompletionsEndpointPath = "/completions/"
 
 // Completions Endpoint
@@ -8,11 +15,17 @@ const CompletionsEndpointPath = "/completions/"
 //	 and can also return the probabilities of alternative tokens at each position.
 type CompletionsEndpoint struct {
 	*endpoint
+	fallback *FallbackPolicy
 }
 
 // Completions Endpoint
 func (c *Client) Completions() *CompletionsEndpoint {
-	return &CompletionsEndpoint{newEndpoint(c, CompletionsEndpointPath)}
+	return &CompletionsEndpoint{endpoint: newDeploymentEndpoint(c, CompletionsEndpointPath)}
+}
+
+// WithFallback returns a copy of the endpoint which falls back along policy's chain when a request fails.
+func (e *CompletionsEndpoint) WithFallback(policy *FallbackPolicy) *CompletionsEndpoint {
+	return &CompletionsEndpoint{endpoint: e.endpoint, fallback: policy}
 }
 
 type CompletionRequest struct {
@@ -35,7 +48,7 @@ type CompletionRequest struct {
 	// Defaults to 1
 	// What sampling temperature to use, between 0 and 2. Higher values like 0.8 will make the output more random, while lower values like 0.2 will make it more focused and deterministic.
 	// We generally recommend altering this or top_p but not both.
-	Temperature int `json:"temperature,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
 	// Defaults to 1
 	// An alternative to sampling with temperature, called nucleus sampling, where the model considers the results of the tokens with top_p probability mass. So 0.1 means only the tokens comprising the top 10% probability mass are considered.
 	// We generally recommend altering this or temperature but not both.
@@ -75,6 +88,45 @@ type CompletionRequest struct {
 	LogitBias map[string]string `json:"logit_bias,omitempty"`
 	// A unique identifier representing your end-user, which can help OpenAI to monitor and detect abuse. Learn more.
 	User string `json:"user,omitempty"`
+	// Defaults to null
+	// If specified, the system will make a best effort to sample deterministically,
+	// such that repeated requests with the same seed and parameters should return the same result.
+	Seed *int `json:"seed,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (c CompletionRequest) MarshalJSON() ([]byte, error) {
+	type alias CompletionRequest
+	return marshalWithExtra(alias(c), c.ExtraFields)
+}
+
+func (r *CompletionRequest) requestModel() string {
+	return r.Model
+}
+
+func (r *CompletionRequest) cacheable() bool {
+	return !r.Stream && deterministic(r.Temperature, r.Seed)
+}
+
+func (r *CompletionRequest) estimateTokens() int {
+	tokens := estimateTextTokens(r.Suffix)
+	for _, p := range r.Prompt {
+		tokens += estimateTextTokens(p)
+	}
+	maxTokens := r.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 16
+	}
+	n := r.N
+	if r.BestOf > n {
+		n = r.BestOf
+	}
+	if n < 1 {
+		n = 1
+	}
+	return tokens + maxTokens*n
 }
 
 type CompletionResponse struct {
@@ -93,13 +145,53 @@ type CompletionResponse struct {
 		CompletionTokens int `json:"completion_tokens"`
 		TotalTokens      int `json:"total_tokens"`
 	} `json:"usage"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the CompletionResponse was decoded from.
+func (c *CompletionResponse) RawJSON() json.RawMessage {
+	return c.rawJSON
+}
+
+func (c *CompletionResponse) UnmarshalJSON(data []byte) error {
+	type alias CompletionResponse
+	extra, err := unmarshalWithExtra(data, (*alias)(c))
+	c.ExtraFields = extra
+	c.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (c CompletionResponse) MarshalJSON() ([]byte, error) {
+	type alias CompletionResponse
+	return marshalWithExtra(alias(c), c.ExtraFields)
+}
+
+func (r *CompletionResponse) tokenUsage() TokenUsage {
+	return TokenUsage{
+		PromptTokens:     r.Usage.PromptTokens,
+		CompletionTokens: r.Usage.CompletionTokens,
+		TotalTokens:      r.Usage.TotalTokens,
+	}
 }
 
 // Creates a completion for the provided prompt and parameters.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/completions/create
-func (e *CompletionsEndpoint) CreateCompletion(req *CompletionRequest) (*CompletionResponse, error) {
-	var resp CompletionResponse
-	err := e.do(e, "POST", "", req, nil, &resp)
-	return &resp, err
+func (e *CompletionsEndpoint) CreateCompletion(req *CompletionRequest, opts ...option.RequestOption) (*CompletionResponse, error) {
+	return e.CreateCompletionWithContext(context.Background(), req, opts...)
+}
+
+// CreateCompletionWithContext is like CreateCompletion but uses ctx for the request.
+func (e *CompletionsEndpoint) CreateCompletionWithContext(ctx context.Context, req *CompletionRequest, opts ...option.RequestOption) (*CompletionResponse, error) {
+	return runWithFallback(ctx, e.fallback, req.Model, opts, func(ctx context.Context, model string, opts []option.RequestOption) (*CompletionResponse, error) {
+		r := *req
+		r.Model = model
+		var resp CompletionResponse
+		err := e.do(ctx, e, "POST", "", &r, nil, &resp, opts...)
+		return &resp, err
+	})
 }
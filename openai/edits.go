@@ -1,5 +1,12 @@
 package openai
 
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
 const EditsEndpointPath = "/edits/"
 
 // Edits Endpoint
@@ -11,7 +18,7 @@ type EditsEndpoint struct {
 
 // Edits Endpoint
 func (c *Client) Edits() *EditsEndpoint {
-	return &EditsEndpoint{newEndpoint(c, EditsEndpointPath)}
+	return &EditsEndpoint{newDeploymentEndpoint(c, EditsEndpointPath)}
 }
 
 type EditRequest struct {
@@ -33,6 +40,22 @@ type EditRequest struct {
 	// An alternative to sampling with temperature, called nucleus sampling, where the model considers the results of the tokens with top_p probability mass. So 0.1 means only the tokens comprising the top 10% probability mass are considered.
 	// We generally recommend altering this or temperature but not both.
 	TopP int `json:"top_p,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (e EditRequest) MarshalJSON() ([]byte, error) {
+	type alias EditRequest
+	return marshalWithExtra(alias(e), e.ExtraFields)
+}
+
+func (r *EditRequest) requestModel() string {
+	return r.Model
+}
+
+func (r *EditRequest) estimateTokens() int {
+	return estimateTextTokens(r.Input) + estimateTextTokens(r.Instruction)
 }
 
 type EditResponse struct {
@@ -47,13 +70,49 @@ type EditResponse struct {
 		CompletionTokens int `json:"completion_tokens"`
 		TotalTokens      int `json:"total_tokens"`
 	} `json:"usage"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the EditResponse was decoded from.
+func (e *EditResponse) RawJSON() json.RawMessage {
+	return e.rawJSON
+}
+
+func (e *EditResponse) UnmarshalJSON(data []byte) error {
+	type alias EditResponse
+	extra, err := unmarshalWithExtra(data, (*alias)(e))
+	e.ExtraFields = extra
+	e.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (e EditResponse) MarshalJSON() ([]byte, error) {
+	type alias EditResponse
+	return marshalWithExtra(alias(e), e.ExtraFields)
+}
+
+func (r *EditResponse) tokenUsage() TokenUsage {
+	return TokenUsage{
+		PromptTokens:     r.Usage.PromptTokens,
+		CompletionTokens: r.Usage.CompletionTokens,
+		TotalTokens:      r.Usage.TotalTokens,
+	}
 }
 
 // Creates a new edit for the provided input, instruction, and parameters.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/edits/create
-func (e *EditsEndpoint) CreateEdit(req *EditRequest) (*EditResponse, error) {
+func (e *EditsEndpoint) CreateEdit(req *EditRequest, opts ...option.RequestOption) (*EditResponse, error) {
+	return e.CreateEditWithContext(context.Background(), req, opts...)
+}
+
+// CreateEditWithContext is like CreateEdit but uses ctx for the request.
+func (e *EditsEndpoint) CreateEditWithContext(ctx context.Context, req *EditRequest, opts ...option.RequestOption) (*EditResponse, error) {
 	var resp EditResponse
-	err := e.do(e, "POST", "", req, nil, &resp)
+	err := e.do(ctx, e, "POST", "", req, nil, &resp, opts...)
 	return &resp, err
 }
@@ -1,5 +1,12 @@
 package openai
 
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
 const EmbeddingsEndpointPath = "/embeddings/"
 
 // Embeddings Endpoint
@@ -8,11 +15,19 @@ const EmbeddingsEndpointPath = "/embeddings/"
 //	 Related guide: [Embeddings]: https://platform.openai.com/docs/guides/embeddings
 type EmbeddingsEndpoint struct {
 	*endpoint
+	fallback *FallbackPolicy
 }
 
 // Completions Endpoint
 func (c *Client) Embeddings() *EmbeddingsEndpoint {
-	return &EmbeddingsEndpoint{newEndpoint(c, EmbeddingsEndpointPath)}
+	return &EmbeddingsEndpoint{endpoint: newDeploymentEndpoint(c, EmbeddingsEndpointPath)}
+}
+
+// WithFallback returns a copy of the endpoint which falls back along policy's chain when a request fails.
+// Vectors from different models are not comparable, so the chain may only switch base URLs:
+// requests fail with ErrIncompatibleFallback if a target names another model.
+func (e *EmbeddingsEndpoint) WithFallback(policy *FallbackPolicy) *EmbeddingsEndpoint {
+	return &EmbeddingsEndpoint{endpoint: e.endpoint, fallback: policy}
 }
 
 type EmbeddingsRequest struct {
@@ -24,6 +39,26 @@ type EmbeddingsRequest struct {
 	Input string `json:"input" binding:"required"`
 	// A unique identifier representing your end-user, which can help OpenAI to monitor and detect abuse. Learn more.
 	User string `json:"user,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (e EmbeddingsRequest) MarshalJSON() ([]byte, error) {
+	type alias EmbeddingsRequest
+	return marshalWithExtra(alias(e), e.ExtraFields)
+}
+
+func (r *EmbeddingsRequest) requestModel() string {
+	return r.Model
+}
+
+func (r *EmbeddingsRequest) cacheable() bool {
+	return true
+}
+
+func (r *EmbeddingsRequest) estimateTokens() int {
+	return estimateTextTokens(r.Input)
 }
 
 type EmbeddingsResponse struct {
@@ -38,13 +73,55 @@ type EmbeddingsResponse struct {
 		PromptTokens int `json:"prompt_tokens"`
 		TotalTokens  int `json:"total_tokens"`
 	} `json:"usage"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the EmbeddingsResponse was decoded from.
+func (e *EmbeddingsResponse) RawJSON() json.RawMessage {
+	return e.rawJSON
+}
+
+func (e *EmbeddingsResponse) UnmarshalJSON(data []byte) error {
+	type alias EmbeddingsResponse
+	extra, err := unmarshalWithExtra(data, (*alias)(e))
+	e.ExtraFields = extra
+	e.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (e EmbeddingsResponse) MarshalJSON() ([]byte, error) {
+	type alias EmbeddingsResponse
+	return marshalWithExtra(alias(e), e.ExtraFields)
+}
+
+func (r *EmbeddingsResponse) tokenUsage() TokenUsage {
+	return TokenUsage{
+		PromptTokens: r.Usage.PromptTokens,
+		TotalTokens:  r.Usage.TotalTokens,
+	}
 }
 
 // Creates an embedding vector representing the input text.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/embeddings
-func (e *EmbeddingsEndpoint) CreateEmbeddings(req *EmbeddingsRequest) (*EmbeddingsResponse, error) {
-	var resp EmbeddingsResponse
-	err := e.do(e, "POST", "", req, nil, &resp)
-	return &resp, err
+func (e *EmbeddingsEndpoint) CreateEmbeddings(req *EmbeddingsRequest, opts ...option.RequestOption) (*EmbeddingsResponse, error) {
+	return e.CreateEmbeddingsWithContext(context.Background(), req, opts...)
+}
+
+// CreateEmbeddingsWithContext is like CreateEmbeddings but uses ctx for the request.
+func (e *EmbeddingsEndpoint) CreateEmbeddingsWithContext(ctx context.Context, req *EmbeddingsRequest, opts ...option.RequestOption) (*EmbeddingsResponse, error) {
+	if e.fallback != nil {
+		if err := e.fallback.checkEmbeddings(req.Model); err != nil {
+			return &EmbeddingsResponse{}, err
+		}
+	}
+	return runWithFallback(ctx, e.fallback, req.Model, opts, func(ctx context.Context, _ string, opts []option.RequestOption) (*EmbeddingsResponse, error) {
+		var resp EmbeddingsResponse
+		err := e.do(ctx, e, "POST", "", req, nil, &resp, opts...)
+		return &resp, err
+	})
 }
@@ -1,9 +1,11 @@
 package openai
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 )
 
 const (
@@ -11,14 +13,17 @@ const (
 )
 
 type endpointI interface {
-	buildURL(endpoint string) (*url.URL, error)
-	newRequest(method string, u *url.URL, body interface{}) (*http.Request, error)
-	doRequest(req *http.Request, v any) error
+	endpointName() string
+	buildURL(endpoint string, model string) (*url.URL, error)
+	newRequest(ctx context.Context, method string, u *url.URL, body interface{}) (*http.Request, error)
+	doRequest(req *http.Request, v any) (*ResponseMeta, error)
 }
 
 type endpoint struct {
 	*Client
 	EndpointPath string
+	// Whether Azure OpenAI serves the endpoint from a model deployment.
+	deploymentScoped bool
 }
 
 type betaEndpoint struct {
@@ -33,6 +38,12 @@ func newEndpoint(c *Client, endpointPath string) *endpoint {
 	return e
 }
 
+func newDeploymentEndpoint(c *Client, endpointPath string) *endpoint {
+	e := newEndpoint(c, endpointPath)
+	e.deploymentScoped = true
+	return e
+}
+
 func newBetaEndpoint(c *Client, endpointPath string) *betaEndpoint {
 	e := &betaEndpoint{
 		endpoint: *newEndpoint(c, endpointPath),
@@ -40,27 +51,50 @@ func newBetaEndpoint(c *Client, endpointPath string) *betaEndpoint {
 	return e
 }
 
-func (e *endpoint) buildURL(endpointPath string) (*url.URL, error) {
+func (e *endpoint) buildURL(endpointPath string, model string) (*url.URL, error) {
 	u, err := url.Parse(endpointPath)
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(e.EndpointPath, u.Path)
-	u.Path = path.Join(apiPath, u.Path)
+	if e.Azure != nil {
+		if e.deploymentScoped {
+			deployment, err := e.Azure.deployment(model)
+			if err != nil {
+				return nil, err
+			}
+			u.Path = path.Join("deployments", url.PathEscape(deployment), u.Path)
+		}
+		u.Path = path.Join(azurePath, u.Path)
+		q := u.Query()
+		q.Set("api-version", e.Azure.APIVersion)
+		u.RawQuery = q.Encode()
+	} else {
+		u.Path = path.Join(apiPath, u.Path)
+	}
 	u.Path = path.Join(e.BaseURL.Path, u.Path)
 	return e.BaseURL.ResolveReference(u), err
 }
 
-func (e *endpoint) doRequest(req *http.Request, v any) error {
+func (e *endpoint) endpointName() string {
+	return strings.Trim(e.EndpointPath, "/")
+}
+
+func (e *endpoint) doRequest(req *http.Request, v any) (*ResponseMeta, error) {
 	return e.Client.doRequest(req, v)
 }
 
-func (e *endpoint) newRequest(method string, u *url.URL, body interface{}) (*http.Request, error) {
-	return e.Client.newRequest(method, u, body)
+func (e *endpoint) newRequest(ctx context.Context, method string, u *url.URL, body interface{}) (*http.Request, error) {
+	return e.Client.newRequest(ctx, method, u, body)
 }
 
-func (e *betaEndpoint) newRequest(method string, u *url.URL, body interface{}) (*http.Request, error) {
-	req, err := e.Client.newRequest(method, u, body)
-	req.Header.Set("OpenAI-Beta", "assistants=v2")
-	return req, err
+func (e *betaEndpoint) newRequest(ctx context.Context, method string, u *url.URL, body interface{}) (*http.Request, error) {
+	req, err := e.Client.newRequest(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.BetaFeatures) > 0 {
+		req.Header.Set("OpenAI-Beta", strings.Join(e.BetaFeatures, ","))
+	}
+	return req, nil
 }
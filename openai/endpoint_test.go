@@ -6,7 +6,10 @@ import (
 
 func TestNewEndpoint(t *testing.T) {
 	testEndpointPath := "testEndpointPath"
-	testClient := NewClient("testapikey")
+	testClient, err := NewClient(WithAPIKey("testapikey"))
+	if err != nil {
+		t.Fatal(err)
+	}
 	e := newEndpoint(testClient, testEndpointPath)
 	if e.BaseURL.String() != testClient.BaseURL.String() {
 		t.Errorf("VendorsEndpoint BaseURL mismatch. Got %s. Want %s", e.BaseURL.String(), testClient.BaseURL.String())
@@ -1,8 +1,36 @@
 package openai
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+)
+
+// Sentinel errors matched by APIError and RequestError with errors.Is.
+//
+//	if errors.Is(err, openai.ErrContextLengthExceeded) {
+//		// shorten the prompt
+//	}
+var (
+	// The request was rejected because of rate limits (HTTP 429).
+	ErrRateLimited = errors.New("openai: rate limited")
+	// The account has run out of credits or reached its monthly spend limit.
+	ErrQuotaExceeded = errors.New("openai: quota exceeded")
+	// The API key is missing or invalid (HTTP 401).
+	ErrAuthentication = errors.New("openai: authentication failed")
+	// The API key lacks permission for the resource (HTTP 403).
+	ErrPermission = errors.New("openai: permission denied")
+	// The requested resource does not exist (HTTP 404).
+	ErrNotFound = errors.New("openai: not found")
+	// The prompt and completion exceed the model's context length.
+	ErrContextLengthExceeded = errors.New("openai: context length exceeded")
+	// The API failed to process the request (HTTP 5xx).
+	ErrServerError = errors.New("openai: server error")
+	// The request timed out, either on the client or on the server.
+	ErrTimeout = errors.New("openai: timeout")
 )
 
 // APIError provides error information returned by the OpenAI API.
@@ -12,12 +40,32 @@ type APIError struct {
 	Param          *string `json:"param,omitempty"`
 	Type           string  `json:"type"`
 	HTTPStatusCode int     `json:"-"`
+	// Number of attempts made before the error was returned.
+	Attempts int `json:"-"`
+	// Value of the x-request-id response header.
+	RequestID string `json:"-"`
 }
 
 // RequestError provides informations about generic request errors.
 type RequestError struct {
 	HTTPStatusCode int
 	Err            error
+	// Number of attempts made before the error was returned.
+	Attempts int
+	// Value of the x-request-id response header.
+	RequestID string
+}
+
+// DecodeError is returned when a successful response body cannot be decoded.
+type DecodeError struct {
+	HTTPStatusCode int
+	// Content-Type header of the response.
+	ContentType string
+	// The start of the response body, at most maxSnippetSize bytes.
+	Body []byte
+	// Go type the body was decoded into.
+	Target string
+	Err    error
 }
 
 type ErrorResponse struct {
@@ -25,11 +73,28 @@ type ErrorResponse struct {
 }
 
 func (e *APIError) Error() string {
+	msg := e.Message
 	if e.HTTPStatusCode > 0 {
-		return fmt.Sprintf("error, status code: %d, message: %s", e.HTTPStatusCode, e.Message)
+		msg = fmt.Sprintf("error, status code: %d, message: %s", e.HTTPStatusCode, e.Message)
 	}
+	if len(e.RequestID) > 0 {
+		msg += ", request id: " + e.RequestID
+	}
+	return msg
+}
 
-	return e.Message
+// Is reports whether the error matches one of the sentinel errors of this package.
+func (e *APIError) Is(target error) bool {
+	code := fmt.Sprint(e.Code)
+	switch target {
+	case ErrQuotaExceeded:
+		return code == "insufficient_quota" || e.Type == "insufficient_quota"
+	case ErrRateLimited:
+		return e.HTTPStatusCode == http.StatusTooManyRequests && !errors.Is(e, ErrQuotaExceeded)
+	case ErrContextLengthExceeded:
+		return code == "context_length_exceeded"
+	}
+	return statusIs(e.HTTPStatusCode, target)
 }
 
 func (e *APIError) UnmarshalJSON(data []byte) (err error) {
@@ -74,9 +139,103 @@ func (e *APIError) UnmarshalJSON(data []byte) (err error) {
 }
 
 func (e *RequestError) Error() string {
-	return fmt.Sprintf("error, status code: %d, message: %s", e.HTTPStatusCode, e.Err)
+	msg := fmt.Sprintf("error, status code: %d, message: %s", e.HTTPStatusCode, e.Err)
+	if e.HTTPStatusCode == 0 {
+		msg = fmt.Sprintf("error, message: %s", e.Err)
+	}
+	if len(e.RequestID) > 0 {
+		msg += ", request id: " + e.RequestID
+	}
+	return msg
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package.
+func (e *RequestError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.HTTPStatusCode == http.StatusTooManyRequests
+	case ErrTimeout:
+		if errors.Is(e.Err, context.DeadlineExceeded) {
+			return true
+		}
+		var netErr net.Error
+		if errors.As(e.Err, &netErr) && netErr.Timeout() {
+			return true
+		}
+	}
+	return statusIs(e.HTTPStatusCode, target)
+}
+
+// statusIs maps HTTP status codes to sentinel errors.
+func statusIs(statusCode int, target error) bool {
+	switch target {
+	case ErrAuthentication:
+		return statusCode == http.StatusUnauthorized
+	case ErrPermission:
+		return statusCode == http.StatusForbidden
+	case ErrNotFound:
+		return statusCode == http.StatusNotFound
+	case ErrServerError:
+		return statusCode >= http.StatusInternalServerError
+	case ErrTimeout:
+		return statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+// IsRetryable reports whether err is a transient failure that may succeed if the request is sent again:
+// rate limits, server errors, timeouts and network failures.
+// Exhausted quota and cancelled contexts are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrQuotaExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrServerError) ||
+		errors.Is(err, ErrTimeout) ||
+		IsRetryableNetworkError(err)
 }
 
 func (e *RequestError) Unwrap() error {
 	return e.Err
 }
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error, status code: %d, failed to decode %q response into %s: %s, body: %q", e.HTTPStatusCode, e.ContentType, e.Target, e.Err, e.Body)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// maxSnippetSize is the maximum number of body bytes kept by a DecodeError.
+const maxSnippetSize = 512
+
+// snippetBuffer keeps the first maxSnippetSize bytes written to it and discards the rest.
+type snippetBuffer struct {
+	buf []byte
+}
+
+func (b *snippetBuffer) Write(p []byte) (int, error) {
+	if n := maxSnippetSize - len(b.buf); n > 0 {
+		if len(p) < n {
+			n = len(p)
+		}
+		b.buf = append(b.buf, p[:n]...)
+	}
+	return len(p), nil
+}
+
+func (b *snippetBuffer) Bytes() []byte {
+	return b.buf
+}
+
+// setAttempts records the number of attempts on errors returned by the API.
+func setAttempts(err error, attempts int) {
+	switch e := err.(type) {
+	case *APIError:
+		e.Attempts = attempts
+	case *RequestError:
+		e.Attempts = attempts
+	}
+}
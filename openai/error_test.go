@@ -0,0 +1,75 @@
+package openai_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestDecodeError(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><body>"+strings.Repeat("Bad Gateway ", 100)+"</body></html>")
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	_, err := client.Models().RetrieveModel("testModelID")
+	var decodeErr *openai.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Unexpected error: %v, expected a DecodeError", err)
+	}
+	if decodeErr.HTTPStatusCode != http.StatusOK || decodeErr.ContentType != "text/html" || decodeErr.Target != "*openai.Model" {
+		t.Errorf("Unexpected DecodeError: %+v", decodeErr)
+	}
+	if !strings.HasPrefix(string(decodeErr.Body), "<html>") || len(decodeErr.Body) > 512 {
+		t.Errorf("Unexpected body snippet: %q", decodeErr.Body)
+	}
+}
+
+func TestErrorSentinels(t *testing.T) {
+	tests := []struct {
+		status   int
+		body     string
+		target   error
+		retrying bool
+	}{
+		{http.StatusTooManyRequests, `{"error":{"message":"slow down","type":"requests","code":"rate_limit_exceeded"}}`, openai.ErrRateLimited, true},
+		{http.StatusTooManyRequests, `{"error":{"message":"no credits","type":"insufficient_quota","code":"insufficient_quota"}}`, openai.ErrQuotaExceeded, false},
+		{http.StatusUnauthorized, `{"error":{"message":"bad key","type":"invalid_request_error","code":"invalid_api_key"}}`, openai.ErrAuthentication, false},
+		{http.StatusForbidden, `{"error":{"message":"denied","type":"invalid_request_error"}}`, openai.ErrPermission, false},
+		{http.StatusNotFound, `{"error":{"message":"no such model","type":"invalid_request_error"}}`, openai.ErrNotFound, false},
+		{http.StatusBadRequest, `{"error":{"message":"too long","type":"invalid_request_error","code":"context_length_exceeded"}}`, openai.ErrContextLengthExceeded, false},
+		{http.StatusBadGateway, `<html>bad gateway</html>`, openai.ErrServerError, true},
+		{http.StatusGatewayTimeout, `{"error":{"message":"timeout","type":"server_error"}}`, openai.ErrTimeout, true},
+	}
+	for _, tt := range tests {
+		ts := openai_test.NewTestServer()
+		ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("x-request-id", "req_123")
+			w.WriteHeader(tt.status)
+			fmt.Fprint(w, tt.body)
+		})
+		ts.HTTPServer.Start()
+
+		client := openai_test.NewTestClient(ts)
+		_, err := client.Models().RetrieveModel("testModelID")
+		if !errors.Is(err, tt.target) {
+			t.Errorf("Status %d: expected %v, got %v", tt.status, tt.target, err)
+		}
+		if openai.IsRetryable(err) != tt.retrying {
+			t.Errorf("Status %d: IsRetryable mismatch. Expected %v", tt.status, tt.retrying)
+		}
+		if !strings.Contains(err.Error(), "request id: req_123") {
+			t.Errorf("Status %d: request id missing from %q", tt.status, err.Error())
+		}
+		ts.HTTPServer.Close()
+	}
+}
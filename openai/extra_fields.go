@@ -0,0 +1,99 @@
+package openai
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Response types keep the JSON fields the SDK does not model yet in an ExtraFields map,
+// and the undecoded object in RawJSON, so new API fields are visible before the structs catch up:
+//
+//	var tier string
+//	err := json.Unmarshal(resp.ExtraFields["service_tier"], &tier)
+//
+// Request types accept additional fields in an ExtraFields map which is merged into the
+// encoded body, replacing any modelled field with the same name.
+
+// unmarshalWithExtra decodes data into v, a pointer to an alias of a response type,
+// and returns the object fields which do not match a field of v.
+func unmarshalWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not an object, e.g. null.
+		return nil, nil
+	}
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	for name := range fields {
+		if known[strings.ToLower(name)] {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// marshalWithExtra encodes v, an alias of a request or response type, with extra merged into the object.
+// The fields are merged undecoded, so numbers keep their exact encoding.
+func marshalWithExtra[V any](v any, extra map[string]V) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if fields[name], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> map[string]bool
+
+// jsonFieldNames returns the lower-cased JSON names of the fields of struct type t,
+// matching encoding/json's case-insensitive field lookup.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if names, ok := jsonFieldCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n := range jsonFieldNames(ft) {
+					names[n] = true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	jsonFieldCache.Store(t, names)
+	return names
+}
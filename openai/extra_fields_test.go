@@ -0,0 +1,196 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("JSON mismatch.\nGot:      %s\nExpected: %s", gb, wb)
+	}
+}
+
+func TestResponseExtraFieldsRoundTrip(t *testing.T) {
+	data := `{
+		"id": "chatcmpl-123",
+		"object": "chat.completion",
+		"model": "gpt-4o-2024-08-06",
+		"created": 1700000000,
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}, "finish_reason": "stop"}],
+		"usage": {"prompt_tokens": 1, "completion_tokens": 1, "total_tokens": 2},
+		"service_tier": "default",
+		"system_fingerprint": "fp_123"
+	}`
+	var resp openai.ChatCompletionResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.ExtraFields["service_tier"]) != `"default"` || len(resp.ExtraFields) != 1 {
+		t.Errorf("Unexpected extra fields: %v", resp.ExtraFields)
+	}
+	if _, ok := resp.ExtraFields["id"]; ok {
+		t.Error("Modelled field reported as extra")
+	}
+	assertJSONEqual(t, resp.RawJSON(), data)
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, out, data)
+}
+
+func TestListExtraFieldsRoundTrip(t *testing.T) {
+	data := `{"object": "list", "data": [
+		{"id": "vs_1", "object": "vector_store", "created_at": 1, "name": "a", "usage_bytes": 0,
+		 "file_counts": {"in_progress": 0, "completed": 0, "failed": 0, "cancelled": 0, "total": 0},
+		 "status": "completed", "expires_after": {"anchor": "last_active_at", "days": 7},
+		 "expires_at": 0, "last_active_at": 0, "chunking_strategy": {"type": "auto"}}
+	], "first_id": "vs_1", "last_id": "vs_1", "has_more": false}`
+	var page openai.Page[openai.VectorStore]
+	if err := json.Unmarshal([]byte(data), &page); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := page.Data[0].ExtraFields["chunking_strategy"]; !ok {
+		t.Errorf("Missing extra field: %v", page.Data[0].ExtraFields)
+	}
+	out, err := json.Marshal(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, out, data)
+}
+
+func TestRequestExtraFields(t *testing.T) {
+	req := openai.EmbeddingsRequest{
+		Model: "text-embedding-3-small",
+		Input: "test",
+		ExtraFields: map[string]any{
+			"dimensions":      256,
+			"encoding_format": "float",
+		},
+	}
+	out, err := json.Marshal(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, out, `{"model": "text-embedding-3-small", "input": "test", "dimensions": 256, "encoding_format": "float"}`)
+
+	// Extra fields replace modelled fields with the same name.
+	req.ExtraFields = map[string]any{"input": []string{"a", "b"}}
+	out, err = json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, out, `{"model": "text-embedding-3-small", "input": ["a", "b"]}`)
+}
+
+func TestExtraFieldsLargeIntegers(t *testing.T) {
+	seed := 1<<53 + 1
+	req := openai.CompletionRequest{
+		Model:       "gpt-3.5-turbo-instruct",
+		Seed:        &seed,
+		ExtraFields: map[string]any{"logit_bias_seed": int64(1<<62 + 1)},
+	}
+	out, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"seed":9007199254740993`, `"logit_bias_seed":4611686018427387905`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Request JSON %s missing %s", out, want)
+		}
+	}
+
+	data := `{"id":"chatcmpl-123","object":"chat.completion","created":9007199254740993,"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":0,"completion_tokens":0,"total_tokens":0},"trace_id":9007199254740995}`
+	var resp openai.ChatCompletionResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatal(err)
+	}
+	out, err = json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"created":9007199254740993`, `"trace_id":9007199254740995`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Response JSON %s missing %s", out, want)
+		}
+	}
+}
+
+func TestMultimodalRequestExtraFields(t *testing.T) {
+	for _, req := range []any{
+		openai.CreateImageEditRequest{Image: "image.png", Prompt: "a cat", ExtraFields: map[string]any{"quality": "high"}},
+		openai.CreateImageVariationRequest{Image: "image.png", ExtraFields: map[string]any{"quality": "high"}},
+		openai.AudioTranscriptionRequest{File: "audio.mp3", Model: "whisper-1", ExtraFields: map[string]any{"quality": "high"}},
+		openai.AudioTranslationRequest{File: "audio.mp3", Model: "whisper-1", ExtraFields: map[string]any{"quality": "high"}},
+	} {
+		out, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), `"quality":"high"`) {
+			t.Errorf("%T JSON %s missing extra field", req, out)
+		}
+	}
+}
+
+func TestUploadFileExtraFields(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		if got := r.FormValue("expires_after"); got != `{"anchor":"created_at","seconds":3600}` {
+			t.Errorf("expires_after mismatch: %s", got)
+		}
+		if got := r.MultipartForm.Value["purpose"]; len(got) != 1 || got[0] != "batch" {
+			t.Errorf("purpose mismatch: %v", got)
+		}
+		fmt.Fprintln(w, `{"id": "file-1", "object": "file", "purpose": "batch"}`)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	path := filepath.Join(t.TempDir(), "batch.jsonl")
+	if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client := openai_test.NewTestClient(ts)
+	_, err := client.Files().UploadFile(&openai.UploadFileRequest{
+		File:    path,
+		Purpose: "fine-tune",
+		ExtraFields: map[string]any{
+			"purpose":       "batch",
+			"expires_after": map[string]any{"anchor": "created_at", "seconds": 3600},
+		},
+	})
+	if err != nil {
+		t.Fatal(err, "UploadFile error")
+	}
+
+	fileId := "file-1"
+	out, err := json.Marshal(openai.AssistantFileRequest{FileId: &fileId, ExtraFields: map[string]any{"chunking": "auto"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, out, `{"file_id": "file-1", "chunking": "auto"}`)
+}
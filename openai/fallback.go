@@ -0,0 +1,108 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
+// ErrIncompatibleFallback is returned when an embeddings fallback chain switches models,
+// which would mix vectors from different embedding spaces.
+var ErrIncompatibleFallback = errors.New("openai: fallback across incompatible embedding models")
+
+// FallbackTarget - a model and deployment tried when the previous one fails.
+type FallbackTarget struct {
+	// Model to request. Empty keeps the model of the request.
+	Model string
+	// Base URL to send the request to, e.g. another region or an Azure resource.
+	// Empty keeps the base URL of the client.
+	BaseURL string
+}
+
+func (t FallbackTarget) String() string {
+	if t.BaseURL == "" {
+		return t.Model
+	}
+	return t.Model + "@" + t.BaseURL
+}
+
+// FallbackPolicy - re-issues a failed request to the next target of a chain.
+//
+// The request is first sent as is. When it fails with an error accepted by ShouldFallback,
+// it is sent again to each target of Chain in turn until one succeeds. Each attempt goes
+// through the client's retries, circuit breaker and rate limiter, so retries are exhausted
+// before falling back. The error of the last attempt is returned when every target fails.
+//
+//	chat := client.Chat().WithFallback(&openai.FallbackPolicy{
+//		Chain: []openai.FallbackTarget{{Model: "gpt-4o-mini"}},
+//	})
+type FallbackPolicy struct {
+	// Targets tried in order after the request's own model.
+	Chain []FallbackTarget
+	// Reports whether an error should trigger a fallback. Defaults to IsFallbackError.
+	ShouldFallback func(err error) bool
+	// Called before falling back from one target to the next.
+	OnFallback func(from FallbackTarget, to FallbackTarget, err error)
+}
+
+// IsFallbackError reports whether another model or deployment may succeed where err failed:
+// retryable errors, an open circuit and requests exceeding the model's context length.
+func IsFallbackError(err error) bool {
+	return IsRetryable(err) || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrContextLengthExceeded)
+}
+
+func (p *FallbackPolicy) shouldFallback(err error) bool {
+	if p.ShouldFallback == nil {
+		return IsFallbackError(err)
+	}
+	return p.ShouldFallback(err)
+}
+
+// checkEmbeddings returns an error if the chain switches away from model.
+func (p *FallbackPolicy) checkEmbeddings(model string) error {
+	for _, t := range p.Chain {
+		if t.Model != "" && t.Model != model {
+			return fmt.Errorf("%w: %s to %s", ErrIncompatibleFallback, model, t.Model)
+		}
+	}
+	return nil
+}
+
+// runWithFallback calls send for the request's own model, then for each target of p's chain while
+// the error allows a fallback. The ResponseMeta captured from ctx reports the model which served the
+// response and the number of fallbacks.
+func runWithFallback[Resp any](ctx context.Context, p *FallbackPolicy, model string, opts []option.RequestOption, send func(ctx context.Context, model string, opts []option.RequestOption) (Resp, error)) (Resp, error) {
+	if p == nil || len(p.Chain) == 0 {
+		return send(ctx, model, opts)
+	}
+	var meta ResponseMeta
+	attemptCtx := CaptureResponseMeta(ctx, &meta)
+	from := FallbackTarget{Model: model}
+	resp, err := send(attemptCtx, model, opts)
+	fallbacks := 0
+	for _, to := range p.Chain {
+		if err == nil || ctx.Err() != nil || !p.shouldFallback(err) {
+			break
+		}
+		if to.Model == "" {
+			to.Model = model
+		}
+		if p.OnFallback != nil {
+			p.OnFallback(from, to, err)
+		}
+		targetOpts := opts
+		if to.BaseURL != "" {
+			targetOpts = append(append([]option.RequestOption(nil), opts...), option.WithBaseURL(to.BaseURL))
+		}
+		meta = ResponseMeta{}
+		fallbacks++
+		resp, err = send(attemptCtx, to.Model, targetOpts)
+		from = to
+	}
+	meta.Model = from.Model
+	meta.Fallbacks = fallbacks
+	captureResponseMeta(ctx, &meta)
+	return resp, err
+}
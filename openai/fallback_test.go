@@ -0,0 +1,106 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func chatByModel(errs map[string]*openai.APIError) openai_test.ScriptFunc {
+	return func(req map[string]any) (any, error) {
+		model, _ := req["model"].(string)
+		if err, ok := errs[model]; ok {
+			return nil, err
+		}
+		return map[string]any{"object": "chat.completion", "model": model}, nil
+	}
+}
+
+func TestChatFallback(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	fs.ScriptFunc("/v1/chat/completions", chatByModel(map[string]*openai.APIError{
+		"gpt-4o":      {Message: "overloaded", HTTPStatusCode: http.StatusServiceUnavailable},
+		"gpt-4o-mini": {Message: "too long", HTTPStatusCode: http.StatusBadRequest, Code: "context_length_exceeded"},
+	}))
+	var fallbacks []string
+	chat := fs.Client().Chat().WithFallback(&openai.FallbackPolicy{
+		Chain: []openai.FallbackTarget{{Model: "gpt-4o-mini"}, {Model: "gpt-4-turbo"}, {Model: "unused"}},
+		OnFallback: func(from, to openai.FallbackTarget, err error) {
+			fallbacks = append(fallbacks, from.Model+"->"+to.Model)
+		},
+	})
+
+	var meta openai.ResponseMeta
+	ctx := openai.CaptureResponseMeta(context.Background(), &meta)
+	resp, err := chat.CreateChatCompletionWithContext(ctx, &openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletion error")
+	}
+	if resp.Model != "gpt-4-turbo" {
+		t.Errorf("Model mismatch. Got %s. Expected %s", resp.Model, "gpt-4-turbo")
+	}
+	if meta.Model != "gpt-4-turbo" || meta.Fallbacks != 2 || meta.StatusCode != http.StatusOK {
+		t.Errorf("Unexpected response meta: %+v", meta)
+	}
+	if len(fallbacks) != 2 || fallbacks[0] != "gpt-4o->gpt-4o-mini" || fallbacks[1] != "gpt-4o-mini->gpt-4-turbo" {
+		t.Errorf("Unexpected fallbacks: %v", fallbacks)
+	}
+	fs.AssertCalled(t, "POST", "/v1/chat/completions", 3)
+}
+
+func TestChatFallbackStopsOnClientError(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	fs.ScriptFunc("/v1/chat/completions", chatByModel(map[string]*openai.APIError{
+		"gpt-4o": {Message: "bad request", HTTPStatusCode: http.StatusBadRequest},
+	}))
+	chat := fs.Client().Chat().WithFallback(&openai.FallbackPolicy{
+		Chain: []openai.FallbackTarget{{Model: "gpt-4o-mini"}},
+	})
+	_, err := chat.CreateChatCompletion(&openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	fs.AssertCalled(t, "POST", "/v1/chat/completions", 1)
+}
+
+func TestCompletionFallbackBaseURL(t *testing.T) {
+	primary := openai_test.NewFakeServer()
+	defer primary.Close()
+	primary.InjectFault(openai_test.Fault{StatusCode: http.StatusInternalServerError})
+	secondary := openai_test.NewFakeServer()
+	defer secondary.Close()
+
+	completions := primary.Client().Completions().WithFallback(&openai.FallbackPolicy{
+		Chain: []openai.FallbackTarget{{BaseURL: secondary.HTTPServer.URL}},
+	})
+	var meta openai.ResponseMeta
+	ctx := openai.CaptureResponseMeta(context.Background(), &meta)
+	_, err := completions.CreateCompletionWithContext(ctx, &openai.CompletionRequest{Model: "gpt-3.5-turbo-instruct"})
+	if err != nil {
+		t.Fatal(err, "CreateCompletion error")
+	}
+	if meta.Model != "gpt-3.5-turbo-instruct" || meta.Fallbacks != 1 {
+		t.Errorf("Unexpected response meta: %+v", meta)
+	}
+	primary.AssertCalled(t, "POST", "/v1/completions", 1)
+	secondary.AssertCalled(t, "POST", "/v1/completions", 1)
+}
+
+func TestEmbeddingsFallbackRefusesOtherModels(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	embeddings := fs.Client().Embeddings().WithFallback(&openai.FallbackPolicy{
+		Chain: []openai.FallbackTarget{{Model: "text-embedding-3-large"}},
+	})
+	_, err := embeddings.CreateEmbeddings(&openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"})
+	if !errors.Is(err, openai.ErrIncompatibleFallback) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrIncompatibleFallback)
+	}
+	fs.AssertCalled(t, "POST", "/v1/embeddings", 0)
+}
@@ -2,12 +2,15 @@ package openai
 
 import (
 	"bytes"
-	"fmt"
+	"context"
+	"encoding/json"
 	"io"
 	"mime/multipart"
-	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
 )
 
 const FilesEndpointPath = "/files/"
@@ -31,8 +34,33 @@ type File struct {
 	CreatedAt int64  `json:"created_at"`
 	Filename  string `json:"filename"`
 	Purpose   string `json:"purpose"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the File was decoded from.
+func (f *File) RawJSON() json.RawMessage {
+	return f.rawJSON
 }
 
+func (f *File) UnmarshalJSON(data []byte) error {
+	type alias File
+	extra, err := unmarshalWithExtra(data, (*alias)(f))
+	f.ExtraFields = extra
+	f.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (f File) MarshalJSON() ([]byte, error) {
+	type alias File
+	return marshalWithExtra(alias(f), f.ExtraFields)
+}
+
+func (f *File) objectID() string { return f.Id }
+
 type Files struct {
 	Object string `json:"object"`
 	Data   []File `json:"data"`
@@ -40,13 +68,24 @@ type Files struct {
 
 // Returns a list of files that belong to the user's organization.
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/files
-func (e *FilesEndpoint) ListFiles() ([]File, error) {
-	var files Files
-	err := e.do(e, "GET", "", nil, nil, &files)
-	if err == nil && files.Object != "list" {
-		err = fmt.Errorf("expected 'list' object type, got %s", files.Object)
-	}
-	return files.Data, err
+func (e *FilesEndpoint) ListFiles(opts ...option.RequestOption) ([]File, error) {
+	return e.ListFilesWithContext(context.Background(), opts...)
+}
+
+// ListFilesWithContext is like ListFiles but uses ctx for the request.
+func (e *FilesEndpoint) ListFilesWithContext(ctx context.Context, opts ...option.RequestOption) ([]File, error) {
+	page, err := e.ListFilesPage(ctx, nil, opts...)
+	return page.Data, err
+}
+
+// ListFilesPage returns a page of files.
+func (e *FilesEndpoint) ListFilesPage(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[File], error) {
+	return listPage[File](ctx, e.Client, e, "", params, opts...)
+}
+
+// ListFilesAutoPaging iterates over every file, starting from params.
+func (e *FilesEndpoint) ListFilesAutoPaging(ctx context.Context, params *ListParams, opts ...option.RequestOption) *Iter[File] {
+	return newIter(ctx, params, e.ListFilesPage, opts...)
 }
 
 type UploadFileRequest struct {
@@ -54,13 +93,27 @@ type UploadFileRequest struct {
 	File string `json:"file" binding:"required"`
 	// 	ID of the model to use. Only whisper-1 is currently available.
 	Purpose string `json:"purpose" binding:"required"`
+
+	// Additional form fields sent with the upload, e.g. parameters the SDK does not model yet.
+	// Values other than strings are sent JSON encoded, and replace a modelled field with the same name.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (r UploadFileRequest) MarshalJSON() ([]byte, error) {
+	type alias UploadFileRequest
+	return marshalWithExtra(alias(r), r.ExtraFields)
 }
 
 // Upload a file that contains document(s) to be used across various endpoints/features.
 // Currently, the size of all the files uploaded by one organization can be up to 1 GB.
 // Please contact us if you need to increase the storage limit.
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/files
-func (e *FilesEndpoint) UploadFile(req *UploadFileRequest) (*File, error) {
+func (e *FilesEndpoint) UploadFile(req *UploadFileRequest, opts ...option.RequestOption) (*File, error) {
+	return e.UploadFileWithContext(context.Background(), req, opts...)
+}
+
+// UploadFileWithContext is like UploadFile but uses ctx for the request.
+func (e *FilesEndpoint) UploadFileWithContext(ctx context.Context, req *UploadFileRequest, opts ...option.RequestOption) (*File, error) {
 	var b bytes.Buffer
 	writer := multipart.NewWriter(&b)
 	fileData, err := os.Open(req.File)
@@ -68,35 +121,42 @@ func (e *FilesEndpoint) UploadFile(req *UploadFileRequest) (*File, error) {
 		return nil, err
 	}
 	defer fileData.Close()
-	err = writer.WriteField("purpose", req.Purpose)
-	if err != nil {
-		return nil, err
+	if _, ok := req.ExtraFields["purpose"]; !ok {
+		err = writer.WriteField("purpose", req.Purpose)
+		if err != nil {
+			return nil, err
+		}
 	}
-	fieldWriter, err := writer.CreateFormFile("file", filepath.Base(fileData.Name()))
-	if err != nil {
-		return nil, err
+	names := make([]string, 0, len(req.ExtraFields))
+	for name := range req.ExtraFields {
+		names = append(names, name)
 	}
-	_, err = io.Copy(fieldWriter, fileData)
-	if err != nil {
-		return nil, err
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := req.ExtraFields[name].(string)
+		if !ok {
+			data, err := json.Marshal(req.ExtraFields[name])
+			if err != nil {
+				return nil, err
+			}
+			value = string(data)
+		}
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, err
+		}
 	}
-	writer.Close()
-	u, err := e.buildURL("")
+	fieldWriter, err := writer.CreateFormFile("file", filepath.Base(fileData.Name()))
 	if err != nil {
 		return nil, err
 	}
-	r, err := http.NewRequest("POST", u.String(), &b)
+	_, err = io.Copy(fieldWriter, &contextReader{ctx: ctx, r: fileData})
 	if err != nil {
 		return nil, err
 	}
-	r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", e.authToken))
-	if len(e.OrganizationID) > 0 {
-		r.Header.Set("OpenAI-Organization", e.OrganizationID)
-	}
-	r.Header.Set("Content-Type", writer.FormDataContentType())
+	writer.Close()
 
 	var file File
-	err = e.doRequest(r, &file)
+	err = e.do(ctx, e, "POST", "", &formBody{buf: &b, contentType: writer.FormDataContentType()}, nil, &file, opts...)
 	return &file, err
 }
 
@@ -108,9 +168,14 @@ type DeleteFileResponse struct {
 
 // Delete a file.
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/files
-func (e *FilesEndpoint) DeleteFile(fileId string) (bool, error) {
+func (e *FilesEndpoint) DeleteFile(fileId string, opts ...option.RequestOption) (bool, error) {
+	return e.DeleteFileWithContext(context.Background(), fileId, opts...)
+}
+
+// DeleteFileWithContext is like DeleteFile but uses ctx for the request.
+func (e *FilesEndpoint) DeleteFileWithContext(ctx context.Context, fileId string, opts ...option.RequestOption) (bool, error) {
 	var resp DeleteFileResponse
-	err := e.do(e, "DELETE", fileId, nil, nil, &resp)
+	err := e.do(ctx, e, "DELETE", fileId, nil, nil, &resp, opts...)
 	if err != nil {
 		return false, err
 	}
@@ -119,16 +184,26 @@ func (e *FilesEndpoint) DeleteFile(fileId string) (bool, error) {
 
 // Returns information about a specific file.
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/files
-func (e *FilesEndpoint) RetrieveFile(fileId string) (*File, error) {
+func (e *FilesEndpoint) RetrieveFile(fileId string, opts ...option.RequestOption) (*File, error) {
+	return e.RetrieveFileWithContext(context.Background(), fileId, opts...)
+}
+
+// RetrieveFileWithContext is like RetrieveFile but uses ctx for the request.
+func (e *FilesEndpoint) RetrieveFileWithContext(ctx context.Context, fileId string, opts ...option.RequestOption) (*File, error) {
 	var file File
-	err := e.do(e, "GET", fileId, nil, nil, &file)
+	err := e.do(ctx, e, "GET", fileId, nil, nil, &file, opts...)
 	return &file, err
 }
 
 // Returns the contents of the specified file
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/files
-func (e *FilesEndpoint) RetrieveFileContent(fileId string) (*string, error) {
+func (e *FilesEndpoint) RetrieveFileContent(fileId string, opts ...option.RequestOption) (*string, error) {
+	return e.RetrieveFileContentWithContext(context.Background(), fileId, opts...)
+}
+
+// RetrieveFileContentWithContext is like RetrieveFileContent but uses ctx for the request.
+func (e *FilesEndpoint) RetrieveFileContentWithContext(ctx context.Context, fileId string, opts ...option.RequestOption) (*string, error) {
 	var data string
-	err := e.do(e, "GET", fileId+"/content", nil, nil, &data)
+	err := e.do(ctx, e, "GET", fileId+"/content", nil, nil, &data, opts...)
 	return &data, err
 }
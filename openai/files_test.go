@@ -135,4 +135,23 @@ func TestRetrieveFile(t *testing.T) {
 	}
 }
 
-// TODO: Add test for RetrieveFileContent
+func TestRetrieveFileContent(t *testing.T) {
+	testFileId := "testFileId"
+	testContent := "{\"prompt\": \"p\", \"completion\": \"c\"}\n{\"prompt\": \"p2\", \"completion\": \"c2\"}\n"
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/files/"+testFileId+"/content", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		fmt.Fprint(w, testContent)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	content, err := client.Files().RetrieveFileContent(testFileId)
+	if err != nil {
+		t.Fatal(err, "RetrieveFileContent error")
+	}
+	if *content != testContent {
+		t.Errorf("Content mismatch. Got %q. Expected %q", *content, testContent)
+	}
+}
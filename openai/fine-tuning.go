@@ -1,9 +1,10 @@
 package openai
 
 import (
-	"fmt"
-	"net/url"
-	"strconv"
+	"context"
+	"encoding/json"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
 )
 
 const FineTuningEndpointPath = "/fine_tuning/"
@@ -38,14 +39,41 @@ type FineTuningJob struct {
 	ValidationFile *string  `json:"validation_file"`
 	ResultFiles    []string `json:"result_files"`
 	TrainedTokens  int64    `json:"trained_tokens"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
 }
 
+// RawJSON returns the JSON the FineTuningJob was decoded from.
+func (f *FineTuningJob) RawJSON() json.RawMessage {
+	return f.rawJSON
+}
+
+func (f *FineTuningJob) UnmarshalJSON(data []byte) error {
+	type alias FineTuningJob
+	extra, err := unmarshalWithExtra(data, (*alias)(f))
+	f.ExtraFields = extra
+	f.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (f FineTuningJob) MarshalJSON() ([]byte, error) {
+	type alias FineTuningJob
+	return marshalWithExtra(alias(f), f.ExtraFields)
+}
+
+func (j *FineTuningJob) objectID() string { return j.Id }
+
 type FineTuningEvents struct {
 	Object  string            `json:"object"`
 	Data    []FineTuningEvent `json:"data"`
 	HasMore bool              `json:"has_more"`
 }
 
+func (e *FineTuningEvent) objectID() string { return e.Id }
+
 type FineTuningEvent struct {
 	Object    string `json:"object"`
 	Id        string `json:"id"`
@@ -54,6 +82,29 @@ type FineTuningEvent struct {
 	Message   string `json:"message"`
 	// Data	  string `json:"data"`
 	Type string `json:"type"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the FineTuningEvent was decoded from.
+func (f *FineTuningEvent) RawJSON() json.RawMessage {
+	return f.rawJSON
+}
+
+func (f *FineTuningEvent) UnmarshalJSON(data []byte) error {
+	type alias FineTuningEvent
+	extra, err := unmarshalWithExtra(data, (*alias)(f))
+	f.ExtraFields = extra
+	f.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (f FineTuningEvent) MarshalJSON() ([]byte, error) {
+	type alias FineTuningEvent
+	return marshalWithExtra(alias(f), f.ExtraFields)
 }
 
 // EventHandler is a callback that gets called every time event on the SSE
@@ -90,15 +141,28 @@ type CreateFineTuningJobRequest struct {
 	// A string of up to 40 characters that will be added to your fine-tuned model name.
 	// For example, a suffix of "custom-model-name" would produce a model name like ft:gpt-3.5-turbo:openai:custom-model-name:7p4lURel.
 	Suffix string `json:"suffix,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (c CreateFineTuningJobRequest) MarshalJSON() ([]byte, error) {
+	type alias CreateFineTuningJobRequest
+	return marshalWithExtra(alias(c), c.ExtraFields)
 }
 
 // Creates a job that fine-tunes a specified model from a given dataset.
 // Response includes details of the enqueued job including job status and the name of the fine-tuned models once complete.
 // Learn more about Fine-tuning
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/fine-tunes
-func (e *FineTuningEndpoint) CreateFineTuningJob(req *CreateFineTuningJobRequest) (*FineTuningJob, error) {
+func (e *FineTuningEndpoint) CreateFineTuningJob(req *CreateFineTuningJobRequest, opts ...option.RequestOption) (*FineTuningJob, error) {
+	return e.CreateFineTuningJobWithContext(context.Background(), req, opts...)
+}
+
+// CreateFineTuningJobWithContext is like CreateFineTuningJob but uses ctx for the request.
+func (e *FineTuningEndpoint) CreateFineTuningJobWithContext(ctx context.Context, req *CreateFineTuningJobRequest, opts ...option.RequestOption) (*FineTuningJob, error) {
 	var fineTuningJob FineTuningJob
-	err := e.do(e, "POST", "jobs", req, nil, &fineTuningJob)
+	err := e.do(ctx, e, "POST", "jobs", req, nil, &fineTuningJob, opts...)
 	return &fineTuningJob, err
 }
 
@@ -109,36 +173,49 @@ type FineTuningJobs struct {
 }
 
 // Returns a list of paginated fine-tuning job objects.
-func (e *FineTuningEndpoint) ListFineTuningJobs(after *string, limit *int) ([]FineTuningJob, error) {
-	v := url.Values{}
-	if after != nil {
-		v.Add("after", *after)
-	}
-	if limit != nil {
-		v.Add("limit", strconv.Itoa(*limit))
-	}
-	var fineTuningJobs FineTuningJobs
-	err := e.do(e, "GET", "jobs", nil, v, &fineTuningJobs)
-	// TODO: This needs to move somewhere central
-	if err == nil && fineTuningJobs.Object != "list" {
-		err = fmt.Errorf("expected 'list' object type, got %s", fineTuningJobs.Object)
-	}
-	return fineTuningJobs.Data, err
+func (e *FineTuningEndpoint) ListFineTuningJobs(after *string, limit *int, opts ...option.RequestOption) ([]FineTuningJob, error) {
+	return e.ListFineTuningJobsWithContext(context.Background(), after, limit, opts...)
+}
+
+// ListFineTuningJobsWithContext is like ListFineTuningJobs but uses ctx for the request.
+func (e *FineTuningEndpoint) ListFineTuningJobsWithContext(ctx context.Context, after *string, limit *int, opts ...option.RequestOption) ([]FineTuningJob, error) {
+	page, err := e.ListFineTuningJobsPage(ctx, listParams(after, limit), opts...)
+	return page.Data, err
+}
+
+// ListFineTuningJobsPage returns a page of fine-tuning jobs.
+func (e *FineTuningEndpoint) ListFineTuningJobsPage(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[FineTuningJob], error) {
+	return listPage[FineTuningJob](ctx, e.Client, e, "jobs", params, opts...)
+}
+
+// ListFineTuningJobsAutoPaging iterates over every fine-tuning job, starting from params.
+func (e *FineTuningEndpoint) ListFineTuningJobsAutoPaging(ctx context.Context, params *ListParams, opts ...option.RequestOption) *Iter[FineTuningJob] {
+	return newIter(ctx, params, e.ListFineTuningJobsPage, opts...)
 }
 
 // Get info about a fine-tuning job.
 // Returns the fine-tuning object with the given ID.
-func (e *FineTuningEndpoint) GetFineTuningJob(fineTuningJobId string) (*FineTuningJob, error) {
+func (e *FineTuningEndpoint) GetFineTuningJob(fineTuningJobId string, opts ...option.RequestOption) (*FineTuningJob, error) {
+	return e.GetFineTuningJobWithContext(context.Background(), fineTuningJobId, opts...)
+}
+
+// GetFineTuningJobWithContext is like GetFineTuningJob but uses ctx for the request.
+func (e *FineTuningEndpoint) GetFineTuningJobWithContext(ctx context.Context, fineTuningJobId string, opts ...option.RequestOption) (*FineTuningJob, error) {
 	var fineTuningJob FineTuningJob
-	err := e.do(e, "GET", "jobs/"+fineTuningJobId, nil, nil, &fineTuningJob)
+	err := e.do(ctx, e, "GET", "jobs/"+fineTuningJobId, nil, nil, &fineTuningJob, opts...)
 	return &fineTuningJob, err
 }
 
 // Immediately cancel a fine-tune job.
 // Returns the cancelled fine-tuning object.
-func (e *FineTuningEndpoint) CancelFineTuningJob(fineTuningJobId string) (*FineTuningJob, error) {
+func (e *FineTuningEndpoint) CancelFineTuningJob(fineTuningJobId string, opts ...option.RequestOption) (*FineTuningJob, error) {
+	return e.CancelFineTuningJobWithContext(context.Background(), fineTuningJobId, opts...)
+}
+
+// CancelFineTuningJobWithContext is like CancelFineTuningJob but uses ctx for the request.
+func (e *FineTuningEndpoint) CancelFineTuningJobWithContext(ctx context.Context, fineTuningJobId string, opts ...option.RequestOption) (*FineTuningJob, error) {
 	var fineTuningJob FineTuningJob
-	err := e.do(e, "POST", "jobs/"+fineTuningJobId+"/cancel", nil, nil, &fineTuningJob)
+	err := e.do(ctx, e, "POST", "jobs/"+fineTuningJobId+"/cancel", nil, nil, &fineTuningJob, opts...)
 	return &fineTuningJob, err
 }
 
@@ -149,19 +226,36 @@ type ListFineTuningEventsRequest struct {
 	// Number of fine-tuning jobs to retrieve.
 	// Defaults to 20
 	Limit int64 `json:"limit,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (r ListFineTuningEventsRequest) MarshalJSON() ([]byte, error) {
+	type alias ListFineTuningEventsRequest
+	return marshalWithExtra(alias(r), r.ExtraFields)
 }
 
 // Get status updates for a fine-tuning job.
 // Returns a list of fine-tuning event objects.
-func (e *FineTuningEndpoint) ListFineTuningEvents(fineTuningJobId string, after *string, limit *int) ([]FineTuningEvent, error) {
-	v := url.Values{}
-	if after != nil {
-		v.Add("after", *after)
-	}
-	if limit != nil {
-		v.Add("limit", strconv.Itoa(*limit))
-	}
-	var fineTuningEvents FineTuningEvents
-	err := e.do(e, "GET", "jobs/"+fineTuningJobId+"/events", nil, v, &fineTuningEvents)
-	return fineTuningEvents.Data, err
+func (e *FineTuningEndpoint) ListFineTuningEvents(fineTuningJobId string, after *string, limit *int, opts ...option.RequestOption) ([]FineTuningEvent, error) {
+	return e.ListFineTuningEventsWithContext(context.Background(), fineTuningJobId, after, limit, opts...)
+}
+
+// ListFineTuningEventsWithContext is like ListFineTuningEvents but uses ctx for the request.
+func (e *FineTuningEndpoint) ListFineTuningEventsWithContext(ctx context.Context, fineTuningJobId string, after *string, limit *int, opts ...option.RequestOption) ([]FineTuningEvent, error) {
+	page, err := e.ListFineTuningEventsPage(ctx, fineTuningJobId, listParams(after, limit), opts...)
+	return page.Data, err
+}
+
+// ListFineTuningEventsPage returns a page of the events of a fine-tuning job.
+func (e *FineTuningEndpoint) ListFineTuningEventsPage(ctx context.Context, fineTuningJobId string, params *ListParams, opts ...option.RequestOption) (*Page[FineTuningEvent], error) {
+	return listPage[FineTuningEvent](ctx, e.Client, e, "jobs/"+fineTuningJobId+"/events", params, opts...)
+}
+
+// ListFineTuningEventsAutoPaging iterates over every event of a fine-tuning job, starting from params.
+func (e *FineTuningEndpoint) ListFineTuningEventsAutoPaging(ctx context.Context, fineTuningJobId string, params *ListParams, opts ...option.RequestOption) *Iter[FineTuningEvent] {
+	return newIter(ctx, params, func(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[FineTuningEvent], error) {
+		return e.ListFineTuningEventsPage(ctx, fineTuningJobId, params, opts...)
+	}, opts...)
 }
@@ -0,0 +1,117 @@
+package openai
+
+import (
+	"context"
+	"time"
+)
+
+// CallInfo - describes an API call for instrumentation hooks.
+type CallInfo struct {
+	// HTTP method of the call.
+	Method string
+	// Endpoint serving the call, e.g. "chat" or "files".
+	Endpoint string
+	// Path of the request URL.
+	Path string
+	// Model targeted by the call, if any.
+	Model string
+	// Request body, e.g. *ChatCompletionRequest. Nil for calls without a body.
+	Request any
+}
+
+// CallResult - outcome of an API call, reported to instrumentation hooks.
+type CallResult struct {
+	// Decoded response, e.g. *ChatCompletionResponse, or the last *ChatCompletionChunk of a stream.
+	// Only meaningful when Err is nil.
+	Response any
+	// Token usage reported by the response, if any. Nil for responses served from the cache,
+	// which used no tokens.
+	Usage *TokenUsage
+	// Metadata of the HTTP response. Nil when no response was received.
+	Meta *ResponseMeta
+	// Error returned to the caller.
+	Err error
+	// Time taken by the whole call, including retries and rate limiting. Streams end once
+	// they have been read to the end, failed or been closed.
+	Duration time.Duration
+}
+
+// TokenUsage - token counts reported by a response.
+type TokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// Hook - observes every API call made by a Client.
+//
+// Hooks see one call per endpoint method invocation, regardless of retries,
+// which makes them suitable for tracing, metrics and logging.
+type Hook interface {
+	// BeforeCall is called before the call starts. The returned context is used for the call.
+	BeforeCall(ctx context.Context, call *CallInfo) context.Context
+	// AfterCall is called once the call has finished, with the context returned by BeforeCall.
+	AfterCall(ctx context.Context, call *CallInfo, result *CallResult)
+}
+
+// WithHook adds instrumentation hooks to the client.
+func WithHook(hooks ...Hook) ClientOption {
+	return func(c *Client) error {
+		c.Hooks = append(c.Hooks, hooks...)
+		return nil
+	}
+}
+
+// usageReporter is implemented by responses which report token usage.
+type usageReporter interface {
+	tokenUsage() TokenUsage
+}
+
+// usageOf returns the token usage reported by result, if any. Streams end their call
+// with their last chunk, which carries the usage when stream_options.include_usage is set.
+func usageOf(result any) *TokenUsage {
+	switch r := result.(type) {
+	case *ChatCompletionChunk:
+		if r.Usage == nil {
+			return nil
+		}
+		return &TokenUsage{
+			PromptTokens:     r.Usage.PromptTokens,
+			CompletionTokens: r.Usage.CompletionTokens,
+			TotalTokens:      r.Usage.TotalTokens,
+		}
+	case usageReporter:
+		usage := r.tokenUsage()
+		return &usage
+	}
+	return nil
+}
+
+// startCall runs the BeforeCall hooks and returns a function that runs the AfterCall hooks in reverse order.
+func (c *Client) startCall(ctx context.Context, call *CallInfo) (context.Context, func(result any, meta *ResponseMeta, err error)) {
+	if len(c.Hooks) == 0 {
+		return ctx, func(any, *ResponseMeta, error) {}
+	}
+	start := time.Now()
+	ctxs := make([]context.Context, len(c.Hooks))
+	for i, h := range c.Hooks {
+		ctx = h.BeforeCall(ctx, call)
+		ctxs[i] = ctx
+	}
+	return ctx, func(result any, meta *ResponseMeta, err error) {
+		r := &CallResult{
+			Meta:     meta,
+			Err:      err,
+			Duration: time.Since(start),
+		}
+		if err == nil {
+			r.Response = result
+			if meta == nil || !meta.Cached {
+				r.Usage = usageOf(result)
+			}
+		}
+		for i := len(c.Hooks) - 1; i >= 0; i-- {
+			c.Hooks[i].AfterCall(ctxs[i], call, r)
+		}
+	}
+}
@@ -1,5 +1,12 @@
 package openai
 
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
 const ImagesEndpointPath = "/images/"
 
 // Images Endpoint
@@ -12,7 +19,7 @@ type ImagesEndpoint struct {
 
 // Images Endpoint
 func (c *Client) Images() *ImagesEndpoint {
-	return &ImagesEndpoint{newEndpoint(c, ImagesEndpointPath)}
+	return &ImagesEndpoint{newDeploymentEndpoint(c, ImagesEndpointPath)}
 }
 
 type ImagesResponse struct {
@@ -20,9 +27,35 @@ type ImagesResponse struct {
 	Data    []struct {
 		Url string `json:"url"`
 	} `json:"data"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the ImagesResponse was decoded from.
+func (i *ImagesResponse) RawJSON() json.RawMessage {
+	return i.rawJSON
+}
+
+func (i *ImagesResponse) UnmarshalJSON(data []byte) error {
+	type alias ImagesResponse
+	extra, err := unmarshalWithExtra(data, (*alias)(i))
+	i.ExtraFields = extra
+	i.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (i ImagesResponse) MarshalJSON() ([]byte, error) {
+	type alias ImagesResponse
+	return marshalWithExtra(alias(i), i.ExtraFields)
 }
 
 type CreateImageRequest struct {
+	// Defaults to dall-e-2
+	// The model to use for image generation.
+	Model string `json:"model,omitempty"`
 	// A text description of the desired image(s). The maximum length is 1000 characters.
 	Prompt string `json:"prompt" binding:"required"`
 	// Defaults to 1
@@ -36,9 +69,24 @@ type CreateImageRequest struct {
 	ResponseFormat string `json:"response_format,omitempty"`
 	// A unique identifier representing your end-user, which can help OpenAI to monitor and detect abuse. Learn more.
 	User string `json:"user,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (c CreateImageRequest) MarshalJSON() ([]byte, error) {
+	type alias CreateImageRequest
+	return marshalWithExtra(alias(c), c.ExtraFields)
+}
+
+func (r *CreateImageRequest) requestModel() string {
+	return r.Model
 }
 
 type CreateImageEditRequest struct {
+	// Defaults to dall-e-2
+	// The model to use for image generation.
+	Model string `json:"model,omitempty"`
 	// The image to edit. Must be a valid PNG file, less than 4MB, and square. If mask is not provided, image must have transparency, which will be used as the mask.
 	Image string `json:"image" binding:"required"`
 	// An additional image whose fully transparent areas (e.g. where alpha is zero) indicate where image should be edited. Must be a valid PNG file, less than 4MB, and have the same dimensions as image.
@@ -56,9 +104,24 @@ type CreateImageEditRequest struct {
 	ResponseFormat string `json:"response_format,omitempty"`
 	// A unique identifier representing your end-user, which can help OpenAI to monitor and detect abuse. Learn more.
 	User string `json:"user,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (r CreateImageEditRequest) MarshalJSON() ([]byte, error) {
+	type alias CreateImageEditRequest
+	return marshalWithExtra(alias(r), r.ExtraFields)
+}
+
+func (r *CreateImageEditRequest) requestModel() string {
+	return r.Model
 }
 
 type CreateImageVariationRequest struct {
+	// Defaults to dall-e-2
+	// The model to use for image generation.
+	Model string `json:"model,omitempty"`
 	// The image to use as the basis for the variation(s). Must be a valid PNG file, less than 4MB, and square.
 	Image string `json:"image" binding:"required"`
 	// Defaults to 1
@@ -72,31 +135,58 @@ type CreateImageVariationRequest struct {
 	ResponseFormat string `json:"response_format,omitempty"`
 	// A unique identifier representing your end-user, which can help OpenAI to monitor and detect abuse. Learn more.
 	User string `json:"user,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (r CreateImageVariationRequest) MarshalJSON() ([]byte, error) {
+	type alias CreateImageVariationRequest
+	return marshalWithExtra(alias(r), r.ExtraFields)
+}
+
+func (r *CreateImageVariationRequest) requestModel() string {
+	return r.Model
 }
 
 // Creates an image given a prompt.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/images/create
-func (e *ImagesEndpoint) CreateImage(req *CreateImageRequest) (*ImagesResponse, error) {
+func (e *ImagesEndpoint) CreateImage(req *CreateImageRequest, opts ...option.RequestOption) (*ImagesResponse, error) {
+	return e.CreateImageWithContext(context.Background(), req, opts...)
+}
+
+// CreateImageWithContext is like CreateImage but uses ctx for the request.
+func (e *ImagesEndpoint) CreateImageWithContext(ctx context.Context, req *CreateImageRequest, opts ...option.RequestOption) (*ImagesResponse, error) {
 	var resp ImagesResponse
-	err := e.do(e, "POST", "generations", req, nil, &resp)
+	err := e.do(ctx, e, "POST", "generations", req, nil, &resp, opts...)
 	return &resp, err
 }
 
 // Creates an edited or extended image given an original image and a prompt.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/images/create-edit
-func (e *ImagesEndpoint) CreateImageEdit(req *CreateImageEditRequest) (*ImagesResponse, error) {
+func (e *ImagesEndpoint) CreateImageEdit(req *CreateImageEditRequest, opts ...option.RequestOption) (*ImagesResponse, error) {
+	return e.CreateImageEditWithContext(context.Background(), req, opts...)
+}
+
+// CreateImageEditWithContext is like CreateImageEdit but uses ctx for the request.
+func (e *ImagesEndpoint) CreateImageEditWithContext(ctx context.Context, req *CreateImageEditRequest, opts ...option.RequestOption) (*ImagesResponse, error) {
 	var resp ImagesResponse
-	err := e.do(e, "POST", "edits", req, nil, &resp)
+	err := e.do(ctx, e, "POST", "edits", req, nil, &resp, opts...)
 	return &resp, err
 }
 
 // Creates a variation of a given image.
 //
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/images/create-variation
-func (e *ImagesEndpoint) CreateImageVariation(req *CreateImageVariationRequest) (*ImagesResponse, error) {
+func (e *ImagesEndpoint) CreateImageVariation(req *CreateImageVariationRequest, opts ...option.RequestOption) (*ImagesResponse, error) {
+	return e.CreateImageVariationWithContext(context.Background(), req, opts...)
+}
+
+// CreateImageVariationWithContext is like CreateImageVariation but uses ctx for the request.
+func (e *ImagesEndpoint) CreateImageVariationWithContext(ctx context.Context, req *CreateImageVariationRequest, opts ...option.RequestOption) (*ImagesResponse, error) {
 	var resp ImagesResponse
-	err := e.do(e, "POST", "edits", req, nil, &resp)
+	err := e.do(ctx, e, "POST", "edits", req, nil, &resp, opts...)
 	return &resp, err
 }
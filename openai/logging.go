@@ -0,0 +1,154 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// redactedHeaders are never logged in clear text.
+var redactedHeaders = []string{"Authorization", "Api-Key", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+const redacted = "[REDACTED]"
+
+// LogOptions - controls what the client logs about each request.
+type LogOptions struct {
+	// Level of successful requests. Failed requests are logged at slog.LevelWarn or above.
+	Level slog.Level
+	// Log request and response headers. Authentication headers are always redacted.
+	LogHeaders bool
+	// Log JSON request and response bodies.
+	LogBodies bool
+	// Maximum number of body bytes logged. Defaults to 2048.
+	MaxBodyBytes int
+	// JSON fields, at any depth, whose values are redacted from logged bodies.
+	RedactFields []string
+}
+
+// WithLogger logs every request sent by the client to logger.
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	client, err := openai.NewClient(
+//		openai.WithAPIKey(key),
+//		openai.WithLogger(logger, openai.LogOptions{Level: slog.LevelDebug, LogBodies: true}),
+//	)
+func WithLogger(logger *slog.Logger, opts LogOptions) ClientOption {
+	return WithMiddleware(loggingMiddleware(logger, opts))
+}
+
+func loggingMiddleware(logger *slog.Logger, opts LogOptions) Middleware {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 2048
+	}
+	redactFields := make(map[string]bool, len(opts.RedactFields))
+	for _, f := range opts.RedactFields {
+		redactFields[strings.ToLower(f)] = true
+	}
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			}
+			if opts.LogHeaders {
+				attrs = append(attrs, slog.Any("request_headers", redactHeaders(req.Header)))
+			}
+			if opts.LogBodies && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					data, _ := io.ReadAll(body)
+					body.Close()
+					attrs = append(attrs, slog.String("request_body", formatBody(req.Header.Get("Content-Type"), data, redactFields, opts.MaxBodyBytes)))
+				}
+			}
+
+			start := time.Now()
+			res, err := next(req)
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+			level := opts.Level
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, maxLevel(level, slog.LevelWarn), "openai request failed", attrs...)
+				return res, err
+			}
+			attrs = append(attrs,
+				slog.Int("status", res.StatusCode),
+				slog.String("request_id", res.Header.Get("x-request-id")),
+			)
+			if opts.LogHeaders {
+				attrs = append(attrs, slog.Any("response_headers", redactHeaders(res.Header)))
+			}
+			contentType := res.Header.Get("Content-Type")
+			if opts.LogBodies && res.Body != nil && !strings.HasPrefix(contentType, "text/event-stream") {
+				data, readErr := io.ReadAll(res.Body)
+				res.Body.Close()
+				res.Body = io.NopCloser(bytes.NewReader(data))
+				if readErr == nil {
+					attrs = append(attrs, slog.String("response_body", formatBody(contentType, data, redactFields, opts.MaxBodyBytes)))
+				}
+			}
+			if res.StatusCode >= http.StatusBadRequest {
+				level = maxLevel(level, slog.LevelWarn)
+			}
+			logger.LogAttrs(ctx, level, "openai request", attrs...)
+			return res, nil
+		}
+	}
+}
+
+func maxLevel(a, b slog.Level) slog.Level {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func redactHeaders(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range redactedHeaders {
+		if _, ok := h[http.CanonicalHeaderKey(name)]; ok {
+			h.Set(name, redacted)
+		}
+	}
+	return h
+}
+
+// formatBody renders a body for logging: JSON bodies have the given fields redacted,
+// other content types are summarised, and the result is truncated to maxBytes.
+func formatBody(contentType string, data []byte, redactFields map[string]bool, maxBytes int) string {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		if strings.HasPrefix(contentType, "multipart/") || !strings.HasPrefix(contentType, "text/") {
+			return "[" + contentType + " body omitted]"
+		}
+	} else {
+		data, _ = json.Marshal(redactJSON(v, redactFields))
+	}
+	if len(data) > maxBytes {
+		return string(data[:maxBytes]) + "...[truncated]"
+	}
+	return string(data)
+}
+
+func redactJSON(v any, fields map[string]bool) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if fields[strings.ToLower(k)] {
+				t[k] = redacted
+			} else {
+				t[k] = redactJSON(val, fields)
+			}
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redactJSON(val, fields)
+		}
+	}
+	return v
+}
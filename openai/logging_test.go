@@ -0,0 +1,53 @@
+package openai_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestLoggerRedaction(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("x-request-id", "req_123")
+		resBytes, _ := json.Marshal(openai.EmbeddingsResponse{Object: "list", Model: "testModelID"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := openai_test.NewTestClient(ts, openai.WithLogger(logger, openai.LogOptions{
+		Level:        slog.LevelDebug,
+		LogHeaders:   true,
+		LogBodies:    true,
+		RedactFields: []string{"input"},
+	}))
+	_, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "testModelID", Input: "my secret text"})
+	if err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid log output %q: %v", buf.String(), err)
+	}
+	out := buf.String()
+	if strings.Contains(out, openai_test.GetTestAuthToken()) || strings.Contains(out, "my secret text") {
+		t.Errorf("Secrets leaked into log output: %s", out)
+	}
+	if record["method"] != "POST" || record["path"] != "/v1/embeddings" || record["status"] != float64(200) || record["request_id"] != "req_123" {
+		t.Errorf("Unexpected log record: %v", record)
+	}
+	if !strings.Contains(record["request_body"].(string), `"input":"[REDACTED]"`) {
+		t.Errorf("Unexpected request body: %v", record["request_body"])
+	}
+}
@@ -0,0 +1,139 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// Names of the metrics emitted by the client.
+const (
+	// Counter of API calls, labelled by endpoint, model and status.
+	MetricRequests = "openai_requests_total"
+	// Counter of failed API calls, labelled by endpoint, model and error type.
+	MetricErrors = "openai_request_errors_total"
+	// Histogram of API call latency in seconds, labelled by endpoint and model.
+	MetricDuration = "openai_request_duration_seconds"
+	// Counter of tokens reported by responses, labelled by endpoint, model and token type.
+	MetricTokens = "openai_tokens_total"
+	// Counter of the estimated cost of API calls, labelled by endpoint and model.
+	// Only emitted for models with a price configured by WithPricing.
+	MetricCost = "openai_cost_total"
+)
+
+// Metrics - receives the measurements of every API call.
+//
+// Labels always include "endpoint" and "model"; model is empty for calls which do not target a model.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// AddCounter increases the counter name by value.
+	AddCounter(name string, value float64, labels map[string]string)
+	// ObserveHistogram records value in the histogram name.
+	ObserveHistogram(name string, value float64, labels map[string]string)
+}
+
+// ModelPrice - the price of a model per million tokens, e.g. in US dollars.
+type ModelPrice struct {
+	PromptPerMillion     float64
+	CompletionPerMillion float64
+}
+
+// cost returns the price of the tokens in u.
+func (p ModelPrice) cost(u *TokenUsage) float64 {
+	return (float64(u.PromptTokens)*p.PromptPerMillion + float64(u.CompletionTokens)*p.CompletionPerMillion) / 1e6
+}
+
+// MetricsOption - configures the metrics reported by WithMetrics.
+type MetricsOption func(h *metricsHook)
+
+// WithPricing enables the cost metric for the models in pricing, keyed by the model of the request.
+//
+//	openai.WithMetrics(metrics, openai.WithPricing(map[string]openai.ModelPrice{
+//		"gpt-4o": {PromptPerMillion: 2.5, CompletionPerMillion: 10},
+//	}))
+func WithPricing(pricing map[string]ModelPrice) MetricsOption {
+	return func(h *metricsHook) {
+		h.pricing = pricing
+	}
+}
+
+// WithMetrics reports the measurements of every API call to m.
+func WithMetrics(m Metrics, opts ...MetricsOption) ClientOption {
+	h := &metricsHook{metrics: m}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return WithHook(h)
+}
+
+type metricsHook struct {
+	metrics Metrics
+	pricing map[string]ModelPrice
+}
+
+func (h *metricsHook) BeforeCall(ctx context.Context, call *CallInfo) context.Context {
+	return ctx
+}
+
+func (h *metricsHook) AfterCall(ctx context.Context, call *CallInfo, result *CallResult) {
+	labels := func(kv ...string) map[string]string {
+		l := map[string]string{"endpoint": call.Endpoint, "model": call.Model}
+		for i := 0; i+1 < len(kv); i += 2 {
+			l[kv[i]] = kv[i+1]
+		}
+		return l
+	}
+	status := ""
+	if result.Meta != nil {
+		status = strconv.Itoa(result.Meta.StatusCode)
+	}
+	h.metrics.AddCounter(MetricRequests, 1, labels("status", status))
+	h.metrics.ObserveHistogram(MetricDuration, result.Duration.Seconds(), labels())
+	if result.Err != nil {
+		h.metrics.AddCounter(MetricErrors, 1, labels("error_type", errorType(result.Err)))
+		return
+	}
+	if u := result.Usage; u != nil {
+		h.metrics.AddCounter(MetricTokens, float64(u.PromptTokens), labels("type", "prompt"))
+		h.metrics.AddCounter(MetricTokens, float64(u.CompletionTokens), labels("type", "completion"))
+		h.metrics.AddCounter(MetricTokens, float64(u.TotalTokens), labels("type", "total"))
+		if price, ok := h.pricing[call.Model]; ok {
+			h.metrics.AddCounter(MetricCost, price.cost(u), labels())
+		}
+	}
+}
+
+// errorType classifies err for the error_type metric label.
+func errorType(err error) string {
+	for _, c := range []struct {
+		target error
+		name   string
+	}{
+		{ErrQuotaExceeded, "quota_exceeded"},
+		{ErrRateLimited, "rate_limited"},
+		{ErrRateLimiterExhausted, "client_rate_limited"},
+		{ErrContextLengthExceeded, "context_length_exceeded"},
+		{ErrAuthentication, "authentication"},
+		{ErrPermission, "permission"},
+		{ErrNotFound, "not_found"},
+		{ErrTimeout, "timeout"},
+		{ErrServerError, "server_error"},
+		{context.Canceled, "canceled"},
+	} {
+		if errors.Is(err, c.target) {
+			return c.name
+		}
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return "decode"
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return "api"
+	}
+	if IsRetryableNetworkError(err) {
+		return "network"
+	}
+	return "other"
+}
@@ -0,0 +1,192 @@
+package openai
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the histogram buckets, in seconds, used by NewPrometheusMetrics when none are given.
+var DefaultLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var metricHelp = map[string]string{
+	MetricRequests: "Number of OpenAI API calls.",
+	MetricErrors:   "Number of failed OpenAI API calls.",
+	MetricDuration: "Latency of OpenAI API calls in seconds.",
+	MetricTokens:   "Number of tokens reported by OpenAI API responses.",
+	MetricCost:     "Estimated cost of OpenAI API calls.",
+}
+
+// PrometheusMetrics - in-memory Metrics implementation which serves the
+// Prometheus text exposition format over HTTP.
+//
+//	metrics := openai.NewPrometheusMetrics()
+//	client, err := openai.NewClient(openai.WithAPIKey(key), openai.WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	counters   map[string]map[string]*promCounter
+	histograms map[string]map[string]*promHistogram
+}
+
+type promCounter struct {
+	labels string
+	value  float64
+}
+
+type promHistogram struct {
+	labels string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics creates Prometheus metrics using the given latency buckets,
+// or DefaultLatencyBuckets if none are given.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &PrometheusMetrics{
+		buckets:    buckets,
+		counters:   make(map[string]map[string]*promCounter),
+		histograms: make(map[string]map[string]*promHistogram),
+	}
+}
+
+// AddCounter implements Metrics.
+func (m *PrometheusMetrics) AddCounter(name string, value float64, labels map[string]string) {
+	key := formatLabels(labels)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	series, ok := m.counters[name]
+	if !ok {
+		series = make(map[string]*promCounter)
+		m.counters[name] = series
+	}
+	c, ok := series[key]
+	if !ok {
+		c = &promCounter{labels: key}
+		series[key] = c
+	}
+	c.value += value
+}
+
+// ObserveHistogram implements Metrics.
+func (m *PrometheusMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	key := formatLabels(labels)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	series, ok := m.histograms[name]
+	if !ok {
+		series = make(map[string]*promHistogram)
+		m.histograms[name] = series
+	}
+	h, ok := series[key]
+	if !ok {
+		h = &promHistogram{labels: key, counts: make([]uint64, len(m.buckets))}
+		series[key] = h
+	}
+	for i, upper := range m.buckets {
+		if value <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format to w.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var b strings.Builder
+	for _, name := range sortedKeys(m.counters) {
+		writeHeader(&b, name, "counter")
+		series := m.counters[name]
+		for _, key := range sortedKeys(series) {
+			fmt.Fprintf(&b, "%s%s %s\n", name, braces(key), formatFloat(series[key].value))
+		}
+	}
+	for _, name := range sortedKeys(m.histograms) {
+		writeHeader(&b, name, "histogram")
+		series := m.histograms[name]
+		for _, key := range sortedKeys(series) {
+			h := series[key]
+			for i, upper := range m.buckets {
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name, braces(joinLabels(key, `le="`+formatFloat(upper)+`"`)), h.counts[i])
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, braces(joinLabels(key, `le="+Inf"`)), h.count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", name, braces(key), formatFloat(h.sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, braces(key), h.count)
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeHeader(b *strings.Builder, name string, typ string) {
+	if help, ok := metricHelp[name]; ok {
+		fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	}
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+}
+
+// formatLabels encodes labels sorted by name, e.g. `endpoint="chat",model="gpt-4o"`.
+func formatLabels(labels map[string]string) string {
+	names := sortedKeys(labels)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + `="` + escapeLabelValue(labels[name]) + `"`
+	}
+	return strings.Join(parts, ",")
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func joinLabels(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "," + b
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
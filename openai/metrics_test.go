@@ -0,0 +1,149 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		resp := openai.EmbeddingsResponse{Object: "list", Model: "testModelID"}
+		resp.Usage.PromptTokens = 8
+		resp.Usage.TotalTokens = 8
+		resBytes, _ := json.Marshal(resp)
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.RegisterHandler("/v1/models/missingModelID", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error":{"message":"not found","type":"invalid_request_error"}}`)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	metrics := openai.NewPrometheusMetrics(0.5, 1)
+	client := openai_test.NewTestClient(ts, openai.WithMetrics(metrics, openai.WithPricing(map[string]openai.ModelPrice{
+		"testModelID": {PromptPerMillion: 0.5},
+	})))
+	for i := 0; i < 2; i++ {
+		if _, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "testModelID", Input: "test"}); err != nil {
+			t.Fatal(err, "CreateEmbeddings error")
+		}
+	}
+	if _, err := client.Models().RetrieveModel("missingModelID"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, line := range []string{
+		"# TYPE openai_requests_total counter",
+		`openai_requests_total{endpoint="embeddings",model="testModelID",status="200"} 2`,
+		`openai_requests_total{endpoint="models",model="",status="404"} 1`,
+		`openai_request_errors_total{endpoint="models",error_type="not_found",model=""} 1`,
+		`openai_tokens_total{endpoint="embeddings",model="testModelID",type="prompt"} 16`,
+		`openai_tokens_total{endpoint="embeddings",model="testModelID",type="total"} 16`,
+		`openai_cost_total{endpoint="embeddings",model="testModelID"} 8e-06`,
+		"# TYPE openai_request_duration_seconds histogram",
+		`openai_request_duration_seconds_bucket{endpoint="embeddings",model="testModelID",le="+Inf"} 2`,
+		`openai_request_duration_seconds_count{endpoint="embeddings",model="testModelID"} 2`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("Metrics output missing %q:\n%s", line, body)
+		}
+	}
+}
+
+func TestMetricsCachedResponses(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	metrics := openai.NewPrometheusMetrics()
+	client := fs.Client(
+		openai.WithCache(openai.NewMemoryCache(100), time.Hour),
+		openai.WithMetrics(metrics, openai.WithPricing(map[string]openai.ModelPrice{
+			"text-embedding-3-small": {PromptPerMillion: 1e6},
+		})),
+	)
+
+	req := openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"}
+	var prompt int
+	for i := 0; i < 3; i++ {
+		resp, err := client.Embeddings().CreateEmbeddings(&req)
+		if err != nil {
+			t.Fatal(err, "CreateEmbeddings error")
+		}
+		prompt = resp.Usage.PromptTokens
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", 1)
+
+	// Cached responses count as requests but not as tokens or cost.
+	var out strings.Builder
+	metrics.WriteTo(&out)
+	for _, line := range []string{
+		`openai_requests_total{endpoint="embeddings",model="text-embedding-3-small",status="200"} 3`,
+		fmt.Sprintf(`openai_tokens_total{endpoint="embeddings",model="text-embedding-3-small",type="prompt"} %d`, prompt),
+		fmt.Sprintf(`openai_cost_total{endpoint="embeddings",model="text-embedding-3-small"} %d`, prompt),
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("Metrics output missing %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestMetricsStreamedResponses(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	metrics := openai.NewPrometheusMetrics(0.05)
+	client := fs.Client(openai.WithMetrics(metrics))
+
+	stream, err := client.Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{
+		Model:         "gpt-4o",
+		Messages:      []openai.ChatMessage{openai.UserMessage("Hello")},
+		StreamOptions: &openai.ChatCompletionStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+
+	// The call only ends once the stream has been read.
+	var out strings.Builder
+	metrics.WriteTo(&out)
+	if strings.Contains(out.String(), "openai_requests_total{") {
+		t.Errorf("Stream counted before it was read:\n%s", out.String())
+	}
+	time.Sleep(60 * time.Millisecond)
+	var usage *openai.ChatCompletionUsage
+	for stream.Next() {
+		if u := stream.Value().Usage; u != nil {
+			usage = u
+		}
+	}
+	if err := stream.Err(); err != nil || usage == nil {
+		t.Fatalf("Unexpected stream end: %v %v", usage, err)
+	}
+
+	out.Reset()
+	metrics.WriteTo(&out)
+	for _, line := range []string{
+		`openai_requests_total{endpoint="chat",model="gpt-4o",status="200"} 1`,
+		fmt.Sprintf(`openai_tokens_total{endpoint="chat",model="gpt-4o",type="completion"} %d`, usage.CompletionTokens),
+		fmt.Sprintf(`openai_tokens_total{endpoint="chat",model="gpt-4o",type="total"} %d`, usage.TotalTokens),
+		`openai_request_duration_seconds_bucket{endpoint="chat",model="gpt-4o",le="0.05"} 0`,
+		`openai_request_duration_seconds_count{endpoint="chat",model="gpt-4o"} 1`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("Metrics output missing %q:\n%s", line, out.String())
+		}
+	}
+}
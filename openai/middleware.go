@@ -0,0 +1,45 @@
+package openai
+
+import "net/http"
+
+// Handler - sends an HTTP request to the API and returns its response.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware - wraps a Handler to inspect or modify outbound requests and their responses.
+//
+// A middleware may mutate the request before calling next, observe the response and error
+// returned by next, or short-circuit by returning a synthetic response without calling next.
+// Middleware runs once per attempt, so a retried request passes through it again.
+//
+//	func requestIDLogger(next openai.Handler) openai.Handler {
+//		return func(req *http.Request) (*http.Response, error) {
+//			res, err := next(req)
+//			if err == nil {
+//				log.Printf("%s %s: %s", req.Method, req.URL.Path, res.Header.Get("x-request-id"))
+//			}
+//			return res, err
+//		}
+//	}
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the client. The first middleware added is the outermost.
+func (c *Client) Use(middleware ...Middleware) {
+	c.Middleware = append(c.Middleware, middleware...)
+}
+
+// WithMiddleware appends middleware to the client.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) error {
+		c.Use(middleware...)
+		return nil
+	}
+}
+
+// handler returns the client's HTTP client wrapped in its middleware chain.
+func (c *Client) handler() Handler {
+	h := Handler(c.httpDo)
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		h = c.Middleware[i](h)
+	}
+	return h
+}
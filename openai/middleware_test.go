@@ -0,0 +1,103 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestMiddlewareOrderAndMutation(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "outer,inner" {
+			t.Errorf("X-Trace mismatch. Got %q", r.Header.Get("X-Trace"))
+		}
+		resBytes, _ := json.Marshal(openai.Model{Object: "model", ID: "testModelID"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	var observed []int
+	tag := func(name string) openai.Middleware {
+		return func(next openai.Handler) openai.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				if v := req.Header.Get("X-Trace"); v != "" {
+					name = v + "," + name
+				}
+				req.Header.Set("X-Trace", name)
+				res, err := next(req)
+				if err == nil {
+					observed = append(observed, res.StatusCode)
+				}
+				return res, err
+			}
+		}
+	}
+	client := openai_test.NewTestClient(ts, openai.WithMiddleware(tag("outer"), tag("inner")))
+	_, err := client.Models().RetrieveModel("testModelID")
+	if err != nil {
+		t.Fatal(err, "RetrieveModel error")
+	}
+	if len(observed) != 2 {
+		t.Errorf("Expected both middleware to observe the response, got %v", observed)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	client := openai_test.NewTestClient(nil)
+	client.Use(func(next openai.Handler) openai.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"id":"cachedModelID","object":"model"}`)),
+				Request:    req,
+			}, nil
+		}
+	})
+	model, err := client.Models().RetrieveModel("testModelID")
+	if err != nil {
+		t.Fatal(err, "RetrieveModel error")
+	}
+	if model.ID != "cachedModelID" {
+		t.Errorf("Model ID mismatch. Got %s. Expected cachedModelID", model.ID)
+	}
+}
+
+func TestMiddlewareUploadFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "train.jsonl")
+	if err := os.WriteFile(filePath, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Gateway-Auth") != "secret" {
+			t.Error("Middleware header missing from upload request")
+		}
+		resBytes, _ := json.Marshal(openai.File{Id: "testFileId", Object: "file"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.Use(func(next openai.Handler) openai.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Gateway-Auth", "secret")
+			return next(req)
+		}
+	})
+	_, err := client.Files().UploadFile(&openai.UploadFileRequest{File: filePath, Purpose: "fine-tune"})
+	if err != nil {
+		t.Error(err, "UploadFile error")
+	}
+}
@@ -1,8 +1,12 @@
 package openai
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
+	"net/http"
 	"net/url"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
 )
 
 const ModelsEndpointPath = "/models/"
@@ -29,6 +33,29 @@ type Model struct {
 	Permission []Permission `json:"permission"`
 	Root       string       `json:"root"`
 	Parent     string       `json:"parent"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the Model was decoded from.
+func (m *Model) RawJSON() json.RawMessage {
+	return m.rawJSON
+}
+
+func (m *Model) UnmarshalJSON(data []byte) error {
+	type alias Model
+	extra, err := unmarshalWithExtra(data, (*alias)(m))
+	m.ExtraFields = extra
+	m.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (m Model) MarshalJSON() ([]byte, error) {
+	type alias Model
+	return marshalWithExtra(alias(m), m.ExtraFields)
 }
 
 // Permission - OpenAPI Permission.
@@ -47,6 +74,13 @@ type Permission struct {
 	IsBlocking         bool        `json:"is_blocking"`
 }
 
+func (m *Model) objectID() string { return m.ID }
+
+// cacheable allows model retrievals to be cached.
+func (e *ModelsEndpoint) cacheable(method string, path string) bool {
+	return method == http.MethodGet && path != ""
+}
+
 type Models struct {
 	Object string  `json:"object"`
 	Data   []Model `json:"data"`
@@ -56,36 +90,57 @@ type Models struct {
 // and provides basic information about each one such as the owner and availability.
 //
 // [OpenAI Documentation]: https://beta.openai.com/docs/api-reference/models/list
-func (e *ModelsEndpoint) ListModels() ([]Model, error) {
-	var models Models
-	err := e.do(e, "GET", "", nil, nil, &models)
-	// TODO: This needs to move somewhere central
-	if err == nil && models.Object != "list" {
-		err = fmt.Errorf("expected 'list' object type, got %s", models.Object)
-	}
-	return models.Data, err
+func (e *ModelsEndpoint) ListModels(opts ...option.RequestOption) ([]Model, error) {
+	return e.ListModelsWithContext(context.Background(), opts...)
+}
+
+// ListModelsWithContext is like ListModels but uses ctx for the request.
+func (e *ModelsEndpoint) ListModelsWithContext(ctx context.Context, opts ...option.RequestOption) ([]Model, error) {
+	page, err := e.ListModelsPage(ctx, nil, opts...)
+	return page.Data, err
+}
+
+// ListModelsPage returns a page of models.
+// The models endpoint returns every model in a single page.
+func (e *ModelsEndpoint) ListModelsPage(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[Model], error) {
+	return listPage[Model](ctx, e.Client, e, "", params, opts...)
+}
+
+// ListModelsAutoPaging iterates over every model.
+func (e *ModelsEndpoint) ListModelsAutoPaging(ctx context.Context, params *ListParams, opts ...option.RequestOption) *Iter[Model] {
+	return newIter(ctx, params, e.ListModelsPage, opts...)
 }
 
 // Retrieves a model instance,
 // providing basic information about the model such as the owner and permissioning.
 //
 // [OpenAI Documentation]: https://beta.openai.com/docs/api-reference/models/retrieve
-func (e *ModelsEndpoint) RetrieveModel(id string) (*Model, error) {
+func (e *ModelsEndpoint) RetrieveModel(id string, opts ...option.RequestOption) (*Model, error) {
+	return e.RetrieveModelWithContext(context.Background(), id, opts...)
+}
+
+// RetrieveModelWithContext is like RetrieveModel but uses ctx for the request.
+func (e *ModelsEndpoint) RetrieveModelWithContext(ctx context.Context, id string, opts ...option.RequestOption) (*Model, error) {
 	var model Model
-	err := e.do(e, "GET", id, nil, nil, &model)
+	err := e.do(ctx, e, "GET", id, nil, nil, &model, opts...)
 	return &model, err
 }
 
 // Delete a fine-tuned model. You must have the Owner role in your organization.
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/fine-tunes/delete-model
-func (e *ModelsEndpoint) DeleteFineTuneModel(id string) (bool, error) {
+func (e *ModelsEndpoint) DeleteFineTuneModel(id string, opts ...option.RequestOption) (bool, error) {
+	return e.DeleteFineTuneModelWithContext(context.Background(), id, opts...)
+}
+
+// DeleteFineTuneModelWithContext is like DeleteFineTuneModel but uses ctx for the request.
+func (e *ModelsEndpoint) DeleteFineTuneModelWithContext(ctx context.Context, id string, opts ...option.RequestOption) (bool, error) {
 	type DeleteResponse struct {
 		Id      string `json:"id"`
 		Object  string `json:"object"`
 		Deleted bool   `json:"deleted"`
 	}
 	var resp DeleteResponse
-	err := e.do(e, "DELETE", url.QueryEscape(id), nil, nil, &resp)
+	err := e.do(ctx, e, "DELETE", url.QueryEscape(id), nil, nil, &resp, opts...)
 	if err != nil {
 		return false, err
 	}
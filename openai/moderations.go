@@ -1,5 +1,12 @@
 package openai
 
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
 const ModerationsEndpointPath = "/moderations/"
 
 // Moderations Endpoint
@@ -22,6 +29,18 @@ type ModerationRequest struct {
 	// Two content moderations models are available: text-moderation-stable and text-moderation-latest.
 	// The default is text-moderation-latest which will be automatically upgraded over time. This ensures you are always using our most accurate model. If you use text-moderation-stable, we will provide advanced notice before updating the model. Accuracy of text-moderation-stable may be slightly lower than for text-moderation-latest.Model string `json:"model" binding:"required"`
 	Model string `json:"model.omntempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (m ModerationRequest) MarshalJSON() ([]byte, error) {
+	type alias ModerationRequest
+	return marshalWithExtra(alias(m), m.ExtraFields)
+}
+
+func (m *ModerationRequest) cacheable() bool {
+	return true
 }
 
 type Moderation struct {
@@ -48,12 +67,40 @@ type Moderation struct {
 		} `json:"category_scores"`
 		Flagged bool `json:"flagged"`
 	} `json:"results"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the Moderation was decoded from.
+func (m *Moderation) RawJSON() json.RawMessage {
+	return m.rawJSON
+}
+
+func (m *Moderation) UnmarshalJSON(data []byte) error {
+	type alias Moderation
+	extra, err := unmarshalWithExtra(data, (*alias)(m))
+	m.ExtraFields = extra
+	m.rawJSON = append(json.RawMessage(nil), data...)
+	return err
+}
+
+func (m Moderation) MarshalJSON() ([]byte, error) {
+	type alias Moderation
+	return marshalWithExtra(alias(m), m.ExtraFields)
 }
 
 // Classifies if text violates OpenAI's Content Policy
 // [OpenAI Documentation]: https://platform.openai.com/docs/api-reference/moderations/create
-func (e *ModerationsEndpoint) CreateModeration(req *ModerationRequest) (*Moderation, error) {
+func (e *ModerationsEndpoint) CreateModeration(req *ModerationRequest, opts ...option.RequestOption) (*Moderation, error) {
+	return e.CreateModerationWithContext(context.Background(), req, opts...)
+}
+
+// CreateModerationWithContext is like CreateModeration but uses ctx for the request.
+func (e *ModerationsEndpoint) CreateModerationWithContext(ctx context.Context, req *ModerationRequest, opts ...option.RequestOption) (*Moderation, error) {
 	var moderation Moderation
-	err := e.do(e, "POST", "", req, nil, &moderation)
+	err := e.do(ctx, e, "POST", "", req, nil, &moderation, opts...)
 	return &moderation, err
 }
@@ -0,0 +1,144 @@
+// Package option provides per-request options for the methods of the openai endpoints.
+//
+//	resp, err := client.Audio().CreateTranscriptionWithContext(ctx, req,
+//		option.WithTimeout(5*time.Minute),
+//		option.WithHeader("X-Gateway-Route", "audio"),
+//	)
+//
+// Client-wide settings are configured with openai.ClientOption values instead.
+package option
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RequestConfig - per-request settings collected from RequestOption values.
+type RequestConfig struct {
+	// Overall timeout of each HTTP attempt, replacing the timeout of the client's HTTP client.
+	Timeout time.Duration
+	// Headers set on the request, replacing client defaults with the same name.
+	Header http.Header
+	// Fields deep-merged into the JSON request body.
+	ExtraBody map[string]any
+	// Query parameters set on the request.
+	Query url.Values
+	// Base URL of the API, replacing the client's base URL.
+	BaseURL *url.URL
+	// Skip the client's response cache.
+	CacheBypass bool
+}
+
+// RequestOption - configures a single request.
+type RequestOption func(*RequestConfig) error
+
+// NewRequestConfig applies opts to an empty RequestConfig.
+func NewRequestConfig(opts ...RequestOption) (*RequestConfig, error) {
+	cfg := &RequestConfig{}
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// WithTimeout sets the overall timeout of each HTTP attempt of the request.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(cfg *RequestConfig) error {
+		if timeout <= 0 {
+			return errors.New("openai: request timeout must be positive")
+		}
+		cfg.Timeout = timeout
+		return nil
+	}
+}
+
+// WithHeader sets a header on the request.
+func WithHeader(key, value string) RequestOption {
+	return func(cfg *RequestConfig) error {
+		if cfg.Header == nil {
+			cfg.Header = http.Header{}
+		}
+		cfg.Header.Set(key, value)
+		return nil
+	}
+}
+
+// WithExtraBody deep-merges fields into the JSON request body,
+// e.g. to send a parameter the SDK does not model yet.
+// Nested objects are merged; any other value replaces the field.
+func WithExtraBody(fields map[string]any) RequestOption {
+	return func(cfg *RequestConfig) error {
+		cfg.ExtraBody = MergeFields(cfg.ExtraBody, fields)
+		return nil
+	}
+}
+
+// WithQuery sets a query parameter on the request.
+func WithQuery(key, value string) RequestOption {
+	return func(cfg *RequestConfig) error {
+		if cfg.Query == nil {
+			cfg.Query = url.Values{}
+		}
+		cfg.Query.Set(key, value)
+		return nil
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header, which lets the API
+// deduplicate a request that is retried.
+func WithIdempotencyKey(key string) RequestOption {
+	return WithHeader("Idempotency-Key", key)
+}
+
+// WithBaseURL sends the request to baseURL, e.g. https://gateway.example.com.
+func WithBaseURL(baseURL string) RequestOption {
+	return func(cfg *RequestConfig) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return errors.New("openai: base URL must be absolute: " + baseURL)
+		}
+		cfg.BaseURL = u
+		return nil
+	}
+}
+
+// WithProject sets the project sent in the OpenAI-Project header.
+func WithProject(projectID string) RequestOption {
+	return WithHeader("OpenAI-Project", projectID)
+}
+
+// WithCacheBypass sends the request to the API even if the client caches responses.
+// The response is neither read from nor written to the cache.
+func WithCacheBypass() RequestOption {
+	return func(cfg *RequestConfig) error {
+		cfg.CacheBypass = true
+		return nil
+	}
+}
+
+// MergeFields deep-merges src into dst and returns dst.
+// Objects present in both are merged recursively; any other value in src replaces the one in dst.
+func MergeFields(dst map[string]any, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for k, v := range src {
+		srcMap, srcOk := v.(map[string]any)
+		dstMap, dstOk := dst[k].(map[string]any)
+		if srcOk && dstOk {
+			dst[k] = MergeFields(dstMap, srcMap)
+		} else if srcOk {
+			dst[k] = MergeFields(nil, srcMap)
+		} else {
+			dst[k] = v
+		}
+	}
+	return dst
+}
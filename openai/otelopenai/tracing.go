@@ -0,0 +1,214 @@
+// Package otelopenai instruments the OpenAI client with OpenTelemetry tracing.
+//
+// Each API call produces a client span carrying the endpoint, HTTP method, status and request ID,
+// together with the gen_ai.* attributes of the OpenTelemetry semantic conventions for generative AI:
+//
+//	client, err := openai.NewClient(
+//		openai.WithAPIKey(key),
+//		otelopenai.WithTracing(),
+//	)
+package otelopenai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/skyscrapr/openai-sdk-go/openai/otelopenai"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option - configures the tracing instrumentation.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. Defaults to the global tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithPropagators sets the propagators used to inject trace context into requests.
+// Defaults to the global propagators.
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = p
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithTracing returns a client option which traces every API call and
+// propagates the trace context in the request headers.
+func WithTracing(opts ...Option) openai.ClientOption {
+	return func(c *openai.Client) error {
+		Instrument(c, opts...)
+		return nil
+	}
+}
+
+// Instrument adds tracing to an existing client.
+func Instrument(c *openai.Client, opts ...Option) {
+	cfg := newConfig(opts)
+	c.Hooks = append(c.Hooks, &tracingHook{
+		tracer: cfg.tracerProvider.Tracer(instrumentationName),
+	})
+	c.Use(propagationMiddleware(cfg.propagators))
+}
+
+// propagationMiddleware injects the trace context of each request into its headers.
+func propagationMiddleware(p propagation.TextMapPropagator) openai.Middleware {
+	return func(next openai.Handler) openai.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			p.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+			return next(req)
+		}
+	}
+}
+
+type tracingHook struct {
+	tracer trace.Tracer
+}
+
+// operationName returns the gen_ai.operation.name of an endpoint, if it is a generative AI operation.
+func operationName(endpoint string) string {
+	switch endpoint {
+	case "chat":
+		return "chat"
+	case "completions":
+		return "text_completion"
+	case "embeddings":
+		return "embeddings"
+	}
+	return ""
+}
+
+func (h *tracingHook) BeforeCall(ctx context.Context, call *openai.CallInfo) context.Context {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", call.Method),
+		attribute.String("url.path", call.Path),
+		attribute.String("openai.endpoint", call.Endpoint),
+	}
+	name := fmt.Sprintf("openai %s %s", call.Method, call.Endpoint)
+	if op := operationName(call.Endpoint); op != "" {
+		name = op
+		attrs = append(attrs,
+			attribute.String("gen_ai.system", "openai"),
+			attribute.String("gen_ai.operation.name", op),
+		)
+	}
+	if call.Model != "" {
+		name += " " + call.Model
+		attrs = append(attrs, attribute.String("gen_ai.request.model", call.Model))
+	}
+	attrs = append(attrs, requestAttributes(call.Request)...)
+	ctx, _ = h.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+func (h *tracingHook) AfterCall(ctx context.Context, call *openai.CallInfo, result *openai.CallResult) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+	if meta := result.Meta; meta != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", meta.StatusCode))
+		if meta.RequestID != "" {
+			span.SetAttributes(attribute.String("openai.request.id", meta.RequestID))
+		}
+		if meta.Attempts > 1 {
+			span.SetAttributes(attribute.Int("openai.attempts", meta.Attempts))
+		}
+	}
+	if result.Err != nil {
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+		span.SetAttributes(attribute.String("error.type", fmt.Sprintf("%T", result.Err)))
+		return
+	}
+	if u := result.Usage; u != nil {
+		span.SetAttributes(
+			attribute.Int("gen_ai.usage.input_tokens", u.PromptTokens),
+			attribute.Int("gen_ai.usage.output_tokens", u.CompletionTokens),
+		)
+	}
+	span.SetAttributes(responseAttributes(result.Response)...)
+}
+
+func requestAttributes(req any) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	add := func(maxTokens int, temperature *float64, topP int) {
+		if maxTokens > 0 {
+			attrs = append(attrs, attribute.Int("gen_ai.request.max_tokens", maxTokens))
+		}
+		if temperature != nil {
+			attrs = append(attrs, attribute.Float64("gen_ai.request.temperature", *temperature))
+		}
+		if topP > 0 {
+			attrs = append(attrs, attribute.Float64("gen_ai.request.top_p", float64(topP)))
+		}
+	}
+	switch r := req.(type) {
+	case *openai.ChatCompletionRequest:
+		add(r.MaxTokens, r.Temperature, r.TopP)
+	case *openai.CompletionRequest:
+		add(r.MaxTokens, r.Temperature, r.TopP)
+	}
+	return attrs
+}
+
+func responseAttributes(resp any) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	var finishReasons []string
+	switch r := resp.(type) {
+	case *openai.ChatCompletionResponse:
+		attrs = append(attrs,
+			attribute.String("gen_ai.response.id", r.Id),
+			attribute.String("gen_ai.response.model", r.Model),
+		)
+		for _, c := range r.Choices {
+			finishReasons = append(finishReasons, c.FinishReason)
+		}
+	case *openai.CompletionResponse:
+		attrs = append(attrs,
+			attribute.String("gen_ai.response.id", r.Id),
+			attribute.String("gen_ai.response.model", r.Model),
+		)
+		for _, c := range r.Choices {
+			finishReasons = append(finishReasons, c.FinishReason)
+		}
+	case *openai.ChatCompletionChunk:
+		// The last chunk of a stream.
+		attrs = append(attrs,
+			attribute.String("gen_ai.response.id", r.Id),
+			attribute.String("gen_ai.response.model", r.Model),
+		)
+	case *openai.EmbeddingsResponse:
+		attrs = append(attrs, attribute.String("gen_ai.response.model", r.Model))
+	}
+	if len(finishReasons) > 0 {
+		attrs = append(attrs, attribute.StringSlice("gen_ai.response.finish_reasons", finishReasons))
+	}
+	return attrs
+}
@@ -0,0 +1,149 @@
+package otelopenai_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/otelopenai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingChatCompletion(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("traceparent") == "" {
+			t.Error("traceparent header missing")
+		}
+		w.Header().Set("x-request-id", "req_123")
+		resp := openai.ChatCompletionResponse{Id: "chatcmpl-1", Object: "chat.completion", Model: "gpt-4o-2024-08-06"}
+		resp.Usage.PromptTokens = 10
+		resp.Usage.CompletionTokens = 5
+		resp.Usage.TotalTokens = 15
+		resBytes, _ := json.Marshal(resp)
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := openai_test.NewTestClient(ts, otelopenai.WithTracing(
+		otelopenai.WithTracerProvider(tp),
+		otelopenai.WithPropagators(propagation.TraceContext{}),
+	))
+
+	_, err := client.Chat().CreateChatCompletion(&openai.ChatCompletionRequest{Model: "gpt-4o", MaxTokens: 100})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletion error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "chat gpt-4o" {
+		t.Errorf("Span name mismatch. Got %q", span.Name())
+	}
+	want := map[attribute.Key]attribute.Value{
+		"gen_ai.operation.name":      attribute.StringValue("chat"),
+		"gen_ai.request.model":       attribute.StringValue("gpt-4o"),
+		"gen_ai.request.max_tokens":  attribute.IntValue(100),
+		"gen_ai.response.id":         attribute.StringValue("chatcmpl-1"),
+		"gen_ai.response.model":      attribute.StringValue("gpt-4o-2024-08-06"),
+		"gen_ai.usage.input_tokens":  attribute.IntValue(10),
+		"gen_ai.usage.output_tokens": attribute.IntValue(5),
+		"http.response.status_code":  attribute.IntValue(200),
+		"openai.request.id":          attribute.StringValue("req_123"),
+	}
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		got[kv.Key] = kv.Value
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Attribute %s mismatch. Got %v. Expected %v", k, got[k].Emit(), v.Emit())
+		}
+	}
+}
+
+func TestTracingError(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/files/testFileId", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error":{"message":"not found","type":"invalid_request_error"}}`)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := openai_test.NewTestClient(ts, otelopenai.WithTracing(otelopenai.WithTracerProvider(tp)))
+
+	if _, err := client.Files().RetrieveFile("testFileId"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Fatalf("Expected 1 error span, got %v", spans)
+	}
+	if spans[0].Name() != "openai GET files" {
+		t.Errorf("Span name mismatch. Got %q", spans[0].Name())
+	}
+}
+
+func TestTracingChatCompletionStream(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := fs.Client(otelopenai.WithTracing(otelopenai.WithTracerProvider(tp)))
+
+	stream, err := client.Chat().CreateChatCompletionStream(&openai.ChatCompletionRequest{
+		Model:         "gpt-4o",
+		Messages:      []openai.ChatMessage{openai.UserMessage("Hello")},
+		StreamOptions: &openai.ChatCompletionStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatal(err, "CreateChatCompletionStream error")
+	}
+	defer stream.Close()
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Fatalf("Span ended before the stream was read: %v", spans)
+	}
+	var last *openai.ChatCompletionChunk
+	for stream.Next() {
+		last = stream.Value()
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err, "Stream error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	want := map[attribute.Key]attribute.Value{
+		"gen_ai.response.id":         attribute.StringValue(last.Id),
+		"gen_ai.usage.input_tokens":  attribute.IntValue(last.Usage.PromptTokens),
+		"gen_ai.usage.output_tokens": attribute.IntValue(last.Usage.CompletionTokens),
+	}
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[0].Attributes() {
+		got[kv.Key] = kv.Value
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Attribute %s mismatch. Got %v. Expected %v", k, got[k].Emit(), v.Emit())
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
+// ListParams - cursor pagination parameters for list endpoints.
+// Zero values are omitted from the request so the API defaults apply.
+type ListParams struct {
+	// Cursor: list objects after this object ID.
+	After string
+	// Cursor: list objects before this object ID.
+	Before string
+	// Number of objects to return per page, between 1 and 100. Defaults to 20.
+	Limit int
+	// Sort order by created_at: "asc" or "desc".
+	Order string
+}
+
+func (p *ListParams) values() url.Values {
+	v := url.Values{}
+	if p == nil {
+		return v
+	}
+	if p.After != "" {
+		v.Set("after", p.After)
+	}
+	if p.Before != "" {
+		v.Set("before", p.Before)
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Order != "" {
+		v.Set("order", p.Order)
+	}
+	return v
+}
+
+// listParams converts the legacy after/limit arguments of list methods.
+func listParams(after *string, limit *int) *ListParams {
+	p := &ListParams{}
+	if after != nil {
+		p.After = *after
+	}
+	if limit != nil {
+		p.Limit = *limit
+	}
+	return p
+}
+
+// Page - a page of objects returned by a list endpoint.
+type Page[T any] struct {
+	Object  string `json:"object"`
+	Data    []T    `json:"data"`
+	FirstID string `json:"first_id"`
+	LastID  string `json:"last_id"`
+	HasMore bool   `json:"has_more"`
+}
+
+// identified is implemented by objects returned from list endpoints.
+type identified interface {
+	objectID() string
+}
+
+// listPage requests a page from a list endpoint.
+// FirstID and LastID are filled in from the data when the API does not return them.
+func listPage[T any](ctx context.Context, c *Client, e endpointI, path string, params *ListParams, opts ...option.RequestOption) (*Page[T], error) {
+	var page Page[T]
+	err := c.do(ctx, e, "GET", path, nil, params.values(), &page, opts...)
+	if err != nil {
+		return &page, err
+	}
+	if page.Object != "list" {
+		return &page, fmt.Errorf("expected 'list' object type, got %s", page.Object)
+	}
+	if len(page.Data) > 0 {
+		if page.FirstID == "" {
+			page.FirstID = idOf(&page.Data[0])
+		}
+		if page.LastID == "" {
+			page.LastID = idOf(&page.Data[len(page.Data)-1])
+		}
+	}
+	return &page, nil
+}
+
+func idOf(v any) string {
+	if i, ok := v.(identified); ok {
+		return i.objectID()
+	}
+	return ""
+}
+
+// Iter - an iterator over every object of a list endpoint, requesting pages as needed.
+//
+//	it := client.Files().ListFilesAutoPaging(ctx, nil)
+//	for it.Next() {
+//		file := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Pages are walked forwards from ListParams.After, or backwards when only ListParams.Before is set.
+type Iter[T any] struct {
+	ctx    context.Context
+	fetch  pageFetcher[T]
+	opts   []option.RequestOption
+	params ListParams
+	page   *Page[T]
+	index  int
+	value  T
+	err    error
+}
+
+type pageFetcher[T any] func(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[T], error)
+
+func newIter[T any](ctx context.Context, params *ListParams, fetch pageFetcher[T], opts ...option.RequestOption) *Iter[T] {
+	it := &Iter[T]{ctx: ctx, fetch: fetch, opts: opts}
+	if params != nil {
+		it.params = *params
+	}
+	return it
+}
+
+// Next advances to the next object, requesting the next page when the current one is exhausted.
+// It returns false when there are no more objects or an error occurred.
+func (it *Iter[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.page == nil || it.index >= len(it.page.Data) {
+		if it.page != nil && (!it.page.HasMore || len(it.page.Data) == 0) {
+			return false
+		}
+		if it.page != nil {
+			if it.params.Before != "" && it.params.After == "" {
+				it.params.Before = it.page.FirstID
+			} else {
+				it.params.After = it.page.LastID
+			}
+		}
+		it.page, it.err = it.fetch(it.ctx, &it.params, it.opts...)
+		if it.err != nil {
+			return false
+		}
+		it.index = 0
+	}
+	it.value = it.page.Data[it.index]
+	it.index++
+	return true
+}
+
+// Value returns the current object.
+func (it *Iter[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iter[T]) Err() error {
+	return it.err
+}
+
+// Page returns the current page.
+func (it *Iter[T]) Page() *Page[T] {
+	return it.page
+}
@@ -0,0 +1,93 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestListAutoPaging(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client()
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		a, err := client.Assistants().CreateAssistant(&openai.AssistantRequest{Model: "gpt-4o"})
+		if err != nil {
+			t.Fatal(err, "CreateAssistant error")
+		}
+		ids = append(ids, a.Id)
+	}
+
+	it := client.Assistants().ListAssistantsAutoPaging(context.Background(), &openai.ListParams{Limit: 2, Order: "asc"})
+	var got []string
+	for it.Next() {
+		got = append(got, it.Value().Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err, "ListAssistantsAutoPaging error")
+	}
+	if fmt.Sprint(got) != fmt.Sprint(ids) {
+		t.Errorf("Iterated IDs mismatch. Got %v. Expected %v", got, ids)
+	}
+	fs.AssertCalled(t, http.MethodGet, "/v1/assistants", 3)
+
+	page, err := client.Assistants().ListAssistantsPage(context.Background(), &openai.ListParams{Limit: 2, After: ids[4]})
+	if err != nil {
+		t.Fatal(err, "ListAssistantsPage error")
+	}
+	if page.FirstID != ids[3] || page.LastID != ids[2] || !page.HasMore {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+
+	// Walk backwards from the oldest assistant.
+	it = client.Assistants().ListAssistantsAutoPaging(context.Background(), &openai.ListParams{Limit: 2, Before: ids[0]})
+	got = nil
+	for it.Next() {
+		got = append(got, it.Value().Id)
+	}
+	if it.Err() != nil || len(got) != 4 {
+		t.Errorf("Unexpected backwards iteration: %v %v", got, it.Err())
+	}
+}
+
+func TestListPageFillsCursors(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/fine_tuning/jobs", func(w http.ResponseWriter, r *http.Request) {
+		// The fine-tuning jobs endpoint returns has_more without first_id and last_id.
+		page := openai.Page[openai.FineTuningJob]{Object: "list", Data: []openai.FineTuningJob{{Id: "ftjob-1"}, {Id: "ftjob-2"}}}
+		if r.URL.Query().Get("after") == "" {
+			page.HasMore = true
+		} else {
+			page.Data = []openai.FineTuningJob{{Id: "ftjob-3"}}
+		}
+		resBytes, _ := json.Marshal(page)
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	page, err := client.FineTuning().ListFineTuningJobsPage(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err, "ListFineTuningJobsPage error")
+	}
+	if page.FirstID != "ftjob-1" || page.LastID != "ftjob-2" {
+		t.Errorf("Unexpected cursors: %s %s", page.FirstID, page.LastID)
+	}
+
+	it := client.FineTuning().ListFineTuningJobsAutoPaging(context.Background(), nil)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil || n != 3 {
+		t.Errorf("Unexpected iteration: %d %v", n, it.Err())
+	}
+}
@@ -0,0 +1,229 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimiterExhausted is returned by a fail-fast RateLimiter when a model has no budget left.
+var ErrRateLimiterExhausted = errors.New("openai: client rate limit exhausted")
+
+// ModelLimit - requests-per-minute and tokens-per-minute budget for a model.
+// A zero value means the corresponding budget is unlimited.
+type ModelLimit struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+// RateLimiter - client-side throttling of requests which target a model.
+//
+// Budgets are tracked per model. Before a request is sent its token cost is estimated
+// from the prompt and max_tokens; once the response arrives the estimate is replaced
+// by the usage reported by the API.
+type RateLimiter struct {
+	// Static limits by model name.
+	Limits map[string]ModelLimit
+	// Limit used for models that are not listed in Limits.
+	Default ModelLimit
+	// Learn limits from the x-ratelimit-* response headers for models without a static limit,
+	// and keep the remaining budget in sync with the headers for every model.
+	LearnFromHeaders bool
+	// Return ErrRateLimiterExhausted instead of waiting for budget to become available.
+	FailFast bool
+
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+	now     func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter with static limits by model which
+// also learns limits from the API response headers.
+func NewRateLimiter(limits map[string]ModelLimit) *RateLimiter {
+	return &RateLimiter{
+		Limits:           limits,
+		LearnFromHeaders: true,
+	}
+}
+
+// modelRequest is implemented by request bodies that target a model.
+type modelRequest interface {
+	requestModel() string
+}
+
+// requestModel returns the model targeted by a request body, if any.
+func requestModel(body interface{}) string {
+	if mr, ok := body.(modelRequest); ok {
+		return mr.requestModel()
+	}
+	return ""
+}
+
+// tokenEstimator is implemented by request bodies whose token cost can be estimated before sending.
+type tokenEstimator interface {
+	estimateTokens() int
+}
+
+// estimateTextTokens approximates the token count of text using the
+// rule of thumb of roughly four characters per token.
+func estimateTextTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+type rateBucket struct {
+	limit    ModelLimit
+	learned  bool
+	requests float64
+	tokens   float64
+	updated  time.Time
+}
+
+// refill adds the budget accrued since the last update.
+func (b *rateBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Minutes()
+	b.updated = now
+	if elapsed <= 0 {
+		return
+	}
+	b.requests = math.Min(float64(b.limit.RequestsPerMinute), b.requests+elapsed*float64(b.limit.RequestsPerMinute))
+	b.tokens = math.Min(float64(b.limit.TokensPerMinute), b.tokens+elapsed*float64(b.limit.TokensPerMinute))
+}
+
+// wait returns how long until a request costing tokens fits in the budget.
+func (b *rateBucket) wait(tokens float64) time.Duration {
+	var d time.Duration
+	if rpm := b.limit.RequestsPerMinute; rpm > 0 && b.requests < 1 {
+		d = time.Duration((1 - b.requests) / float64(rpm) * float64(time.Minute))
+	}
+	if tpm := b.limit.TokensPerMinute; tpm > 0 && b.tokens < tokens {
+		if td := time.Duration((tokens - b.tokens) / float64(tpm) * float64(time.Minute)); td > d {
+			d = td
+		}
+	}
+	return d
+}
+
+type rateReservation struct {
+	model  string
+	tokens int
+	// Tokens taken from the bucket, which is less than tokens for requests larger than the whole budget.
+	deducted float64
+}
+
+func (l *RateLimiter) clock() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// bucket returns the bucket for model. The caller must hold l.mu.
+func (l *RateLimiter) bucket(model string, now time.Time) *rateBucket {
+	if l.buckets == nil {
+		l.buckets = make(map[string]*rateBucket)
+	}
+	b, ok := l.buckets[model]
+	if !ok {
+		limit, ok := l.Limits[model]
+		if !ok {
+			limit = l.Default
+		}
+		b = &rateBucket{
+			limit:    limit,
+			requests: float64(limit.RequestsPerMinute),
+			tokens:   float64(limit.TokensPerMinute),
+			updated:  now,
+		}
+		l.buckets[model] = b
+	}
+	return b
+}
+
+// reserve takes budget for the request body, waiting for it to become available unless FailFast is set.
+// It returns a nil reservation for bodies which do not target a model.
+func (l *RateLimiter) reserve(ctx context.Context, body interface{}) (*rateReservation, error) {
+	model := requestModel(body)
+	if model == "" {
+		return nil, nil
+	}
+	r := &rateReservation{model: model}
+	if te, ok := body.(tokenEstimator); ok {
+		r.tokens = te.estimateTokens()
+	}
+	for {
+		l.mu.Lock()
+		now := l.clock()
+		b := l.bucket(r.model, now)
+		b.refill(now)
+		tokens := float64(r.tokens)
+		if tpm := float64(b.limit.TokensPerMinute); tpm > 0 && tokens > tpm {
+			// A request larger than the whole budget can only ever run with a full bucket.
+			tokens = tpm
+		}
+		d := b.wait(tokens)
+		if d <= 0 {
+			if b.limit.RequestsPerMinute > 0 {
+				b.requests--
+			}
+			if b.limit.TokensPerMinute > 0 {
+				b.tokens -= tokens
+				r.deducted = tokens
+			}
+			l.mu.Unlock()
+			return r, nil
+		}
+		l.mu.Unlock()
+		if l.FailFast {
+			return nil, fmt.Errorf("%w: model %s, retry in %s", ErrRateLimiterExhausted, r.model, d)
+		}
+		if err := sleep(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reconcile replaces the estimated token cost of r with the usage reported in result,
+// and updates the budget from the rate limit headers in meta. A nil meta means no response
+// was received, so the request never reached the API and its budget is refunded.
+func (l *RateLimiter) reconcile(r *rateReservation, meta *ResponseMeta, result interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock()
+	b := l.bucket(r.model, now)
+	b.refill(now)
+	if meta == nil {
+		if b.limit.RequestsPerMinute > 0 {
+			b.requests = math.Min(float64(b.limit.RequestsPerMinute), b.requests+1)
+		}
+		if b.limit.TokensPerMinute > 0 {
+			b.tokens = math.Min(float64(b.limit.TokensPerMinute), b.tokens+r.deducted)
+		}
+		return
+	}
+	if usage := usageOf(result); usage != nil && b.limit.TokensPerMinute > 0 {
+		if used := usage.TotalTokens; used > 0 {
+			b.tokens = math.Min(float64(b.limit.TokensPerMinute), b.tokens+r.deducted-float64(used))
+		}
+	}
+	if !l.LearnFromHeaders {
+		return
+	}
+	rl := meta.RateLimit
+	if _, static := l.Limits[r.model]; !static && (rl.LimitRequests > 0 || rl.LimitTokens > 0) {
+		if !b.learned {
+			b.requests = float64(rl.LimitRequests)
+			b.tokens = float64(rl.LimitTokens)
+		}
+		b.limit = ModelLimit{RequestsPerMinute: rl.LimitRequests, TokensPerMinute: rl.LimitTokens}
+		b.learned = true
+	}
+	if rl.LimitRequests > 0 {
+		b.requests = math.Min(b.requests, float64(rl.RemainingRequests))
+	}
+	if rl.LimitTokens > 0 {
+		b.tokens = math.Min(b.tokens, float64(rl.RemainingTokens))
+	}
+}
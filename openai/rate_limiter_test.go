@@ -0,0 +1,159 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func newEmbeddingsTestServer(header http.Header) *openai_test.TestServer {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		resp := openai.EmbeddingsResponse{Object: "list", Model: "testModelID"}
+		resp.Usage.PromptTokens = 5
+		resp.Usage.TotalTokens = 5
+		resBytes, _ := json.Marshal(resp)
+		fmt.Fprintln(w, string(resBytes))
+	})
+	return ts
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	ts := newEmbeddingsTestServer(nil)
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RateLimiter = openai.NewRateLimiter(map[string]openai.ModelLimit{
+		"testModelID": {RequestsPerMinute: 1},
+	})
+	client.RateLimiter.FailFast = true
+
+	req := openai.EmbeddingsRequest{Model: "testModelID", Input: "test"}
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	_, err := client.Embeddings().CreateEmbeddings(&req)
+	if !errors.Is(err, openai.ErrRateLimiterExhausted) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrRateLimiterExhausted)
+	}
+
+	// Other models are not limited.
+	req.Model = "otherModelID"
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Error(err, "CreateEmbeddings error")
+	}
+}
+
+func TestRateLimiterOversizedRequest(t *testing.T) {
+	ts := newEmbeddingsTestServer(nil)
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RateLimiter = openai.NewRateLimiter(map[string]openai.ModelLimit{
+		"testModelID": {TokensPerMinute: 100},
+	})
+	client.RateLimiter.FailFast = true
+
+	// The request is estimated at 1000 tokens but only the 100 token budget is taken.
+	req := openai.EmbeddingsRequest{Model: "testModelID", Input: strings.Repeat("x", 4000)}
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	// The server reports 5 tokens used, so 95 are refunded rather than the full budget.
+	req.Input = strings.Repeat("x", 400)
+	_, err := client.Embeddings().CreateEmbeddings(&req)
+	if !errors.Is(err, openai.ErrRateLimiterExhausted) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrRateLimiterExhausted)
+	}
+}
+
+func TestRateLimiterBlocks(t *testing.T) {
+	ts := newEmbeddingsTestServer(nil)
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RateLimiter = openai.NewRateLimiter(map[string]openai.ModelLimit{
+		"testModelID": {TokensPerMinute: 10},
+	})
+
+	req := openai.EmbeddingsRequest{Model: "testModelID", Input: "a prompt of about ten tokens in length."}
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Embeddings().CreateEmbeddingsWithContext(ctx, &req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiterLearnsFromHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("x-ratelimit-limit-requests", "100")
+	header.Set("x-ratelimit-remaining-requests", "0")
+	header.Set("x-ratelimit-limit-tokens", "1000")
+	header.Set("x-ratelimit-remaining-tokens", "995")
+	ts := newEmbeddingsTestServer(header)
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RateLimiter = openai.NewRateLimiter(nil)
+	client.RateLimiter.FailFast = true
+
+	req := openai.EmbeddingsRequest{Model: "testModelID", Input: "test"}
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	_, err := client.Embeddings().CreateEmbeddings(&req)
+	if !errors.Is(err, openai.ErrRateLimiterExhausted) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrRateLimiterExhausted)
+	}
+}
+
+func TestRateLimiterRefundsUnsentRequests(t *testing.T) {
+	ts := newEmbeddingsTestServer(nil)
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RateLimiter = openai.NewRateLimiter(map[string]openai.ModelLimit{
+		"testModelID": {RequestsPerMinute: 1, TokensPerMinute: 100},
+	})
+	client.RateLimiter.FailFast = true
+	errBlocked := errors.New("blocked")
+	block := true
+	client.Use(func(next openai.Handler) openai.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if block {
+				return nil, errBlocked
+			}
+			return next(req)
+		}
+	})
+
+	// A request which fails without a response gives its budget back.
+	req := openai.EmbeddingsRequest{Model: "testModelID", Input: strings.Repeat("x", 400)}
+	if _, err := client.Embeddings().CreateEmbeddings(&req); !errors.Is(err, errBlocked) {
+		t.Fatalf("Unexpected error: %v, expected: %v", err, errBlocked)
+	}
+	block = false
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Error(err, "CreateEmbeddings error")
+	}
+}
@@ -0,0 +1,100 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+)
+
+// rawEndpoint - an endpoint for requests made with Client.Do and Client.DoRaw.
+type rawEndpoint struct {
+	*betaEndpoint
+	name string
+}
+
+func (c *Client) rawEndpoint(path string) *rawEndpoint {
+	name, _, _ := strings.Cut(strings.Trim(path, "/"), "/")
+	name, _, _ = strings.Cut(name, "?")
+	return &rawEndpoint{betaEndpoint: newBetaEndpoint(c, "/"), name: name}
+}
+
+func (e *rawEndpoint) endpointName() string {
+	return e.name
+}
+
+// rawResponse receives the undecoded response of a DoRaw or streaming request.
+type rawResponse struct {
+	res *http.Response
+	// end finishes the call once the caller has consumed the response, with the result
+	// reported to hooks and the error the response ended with. Only the first call has effect.
+	end func(result any, err error)
+}
+
+// rawCallBody ends the call of a DoRaw request once its body has been read to the end or closed.
+type rawCallBody struct {
+	io.ReadCloser
+	raw *rawResponse
+}
+
+func (b *rawCallBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.raw.end(b.raw, nil)
+	} else if err != nil {
+		b.raw.end(b.raw, err)
+	}
+	return n, err
+}
+
+func (b *rawCallBody) Close() error {
+	b.raw.end(b.raw, nil)
+	return b.ReadCloser.Close()
+}
+
+// rawBytes is a Do request body which is sent as-is rather than encoded as JSON.
+type rawBytes []byte
+
+// rawBody converts a Do body for encoding: byte slices and strings are sent as-is.
+func rawBody(body any) any {
+	switch b := body.(type) {
+	case []byte:
+		return rawBytes(b)
+	case string:
+		return rawBytes(b)
+	}
+	return body
+}
+
+// Do sends a request to an API endpoint the SDK does not cover yet and decodes the JSON response into out.
+//
+// path is relative to the API root, e.g. "/batches" for https://api.openai.com/v1/batches,
+// and may include a query string. body is encoded as JSON unless it is a []byte or string,
+// which are sent as-is. Raw bodies are sent with a JSON Content-Type; set another one with
+// option.WithHeader("Content-Type", ...). out follows the same rules as the endpoint methods: a *string,
+// *[]byte or io.Writer receives the raw body, and a nil out discards it.
+// The request gets the client's authentication, headers, retries, middleware, hooks and error handling.
+//
+//	var batch map[string]any
+//	err := client.Do(ctx, "POST", "/batches", map[string]any{"input_file_id": fileID}, &batch)
+func (c *Client) Do(ctx context.Context, method string, path string, body any, out any, opts ...option.RequestOption) error {
+	return c.do(ctx, c.rawEndpoint(path), method, path, rawBody(body), nil, out, opts...)
+}
+
+// DoRaw is like Do but returns the HTTP response without reading its body, e.g. to consume a stream.
+// The caller must close the response body. Error responses are returned as errors, as with Do.
+// As with streaming endpoint methods, the client's HTTP timeout does not apply and a per-request
+// option.WithTimeout only bounds the wait for the response headers; cancel ctx to abort reading the body.
+// Hooks, the rate limiter and the circuit breaker see the call end once the body is read to the end or closed.
+func (c *Client) DoRaw(ctx context.Context, method string, path string, body any, opts ...option.RequestOption) (*http.Response, error) {
+	var raw rawResponse
+	ctx = context.WithValue(ctx, streamKey{}, true)
+	err := c.do(ctx, c.rawEndpoint(path), method, path, rawBody(body), nil, &raw, opts...)
+	if err != nil {
+		return nil, err
+	}
+	raw.res.Body = &rawCallBody{ReadCloser: raw.res.Body, raw: &raw}
+	return raw.res, nil
+}
@@ -0,0 +1,151 @@
+package openai_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestClientDo(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/batches", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("OpenAI-Organization") != "org_123" {
+			t.Errorf("Missing organization header: %v", r.Header)
+		}
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("Query mismatch: %s", r.URL.RawQuery)
+		}
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"id":"batch_123","object":"batch","request":%s}`, body)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts, openai.WithOrganization("org_123"))
+	var batch struct {
+		Id      string         `json:"id"`
+		Request map[string]any `json:"request"`
+	}
+	err := client.Do(context.Background(), "POST", "/batches?limit=2", map[string]any{"input_file_id": "file-1"}, &batch)
+	if err != nil {
+		t.Fatal(err, "Do error")
+	}
+	if batch.Id != "batch_123" || batch.Request["input_file_id"] != "file-1" {
+		t.Errorf("Unexpected response: %+v", batch)
+	}
+
+	err = client.Do(context.Background(), "POST", "/batches?limit=2", []byte(`{"input_file_id":"file-2"}`), &batch)
+	if err != nil || batch.Request["input_file_id"] != "file-2" {
+		t.Errorf("Unexpected response: %+v %v", batch, err)
+	}
+
+	err = client.Do(context.Background(), "POST", "/batches?limit=2", `{"input_file_id":"file-3"}`, &batch)
+	if err != nil || batch.Request["input_file_id"] != "file-3" {
+		t.Errorf("Unexpected response: %+v %v", batch, err)
+	}
+
+	err = client.Do(context.Background(), "GET", "/batches/missing", nil, nil)
+	if !errors.Is(err, openai.ErrNotFound) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrNotFound)
+	}
+}
+
+func TestClientDoNonJSONBody(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/uploads/upload_123/parts", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "line 1\nline 2\n" {
+			t.Errorf("Body mismatch. Got %q", body)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "text/plain" {
+			t.Errorf("Content-Type mismatch. Got %q", ct)
+		}
+		fmt.Fprint(w, `{"id":"part_123"}`)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	var part map[string]any
+	err := client.Do(context.Background(), "POST", "/uploads/upload_123/parts", []byte("line 1\nline 2\n"), &part,
+		option.WithHeader("Content-Type", "text/plain"))
+	if err != nil {
+		t.Fatal(err, "Do error")
+	}
+	if part["id"] != "part_123" {
+		t.Errorf("Unexpected response: %v", part)
+	}
+}
+
+func TestClientDoRaw(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: one\n\ndata: two\n\ndata: [DONE]\n\n")
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	res, err := client.DoRaw(context.Background(), "POST", "/responses", map[string]any{"stream": true})
+	if err != nil {
+		t.Fatal(err, "DoRaw error")
+	}
+	defer res.Body.Close()
+	var events []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	if strings.Join(events, ",") != "one,two,[DONE]" {
+		t.Errorf("Unexpected events: %v", events)
+	}
+
+	_, err = client.DoRaw(context.Background(), "GET", "/missing", nil)
+	if !errors.Is(err, openai.ErrNotFound) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrNotFound)
+	}
+}
+
+func TestClientDoRawTimeouts(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/responses", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 5; i++ {
+			fmt.Fprint(w, "data: tick\n\n")
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	// Neither the client timeout nor the per-request timeout cuts the stream off.
+	client := openai_test.NewTestClient(ts, openai.WithTimeout(30*time.Millisecond))
+	res, err := client.DoRaw(context.Background(), "POST", "/responses", map[string]any{"stream": true},
+		option.WithTimeout(30*time.Millisecond))
+	if err != nil {
+		t.Fatal(err, "DoRaw error")
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err, "ReadAll error")
+	}
+	if !strings.HasSuffix(string(body), "data: [DONE]\n\n") {
+		t.Errorf("Unexpected body: %q", body)
+	}
+}
@@ -0,0 +1,130 @@
+package openai_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestRequestOptions(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client(openai.WithProject("proj_client"), openai.WithHeader("X-Gateway", "default"))
+
+	req := openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"}
+	_, err := client.Embeddings().CreateEmbeddings(&req,
+		option.WithHeader("X-Gateway", "audio"),
+		option.WithProject("proj_request"),
+		option.WithIdempotencyKey("key_123"),
+		option.WithQuery("trace", "1"),
+		option.WithExtraBody(map[string]any{"dimensions": 256, "metadata": map[string]any{"a": "1"}}),
+		option.WithExtraBody(map[string]any{"metadata": map[string]any{"b": "2"}}),
+	)
+	if err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	call := fs.LastCall(t, http.MethodPost, "/v1/embeddings")
+	want := map[string]string{
+		"X-Gateway":       "audio",
+		"OpenAI-Project":  "proj_request",
+		"Idempotency-Key": "key_123",
+	}
+	for k, v := range want {
+		if got := call.Header.Get(k); got != v {
+			t.Errorf("Header %s mismatch. Got %q. Expected %q", k, got, v)
+		}
+	}
+	if got := call.Query.Get("trace"); got != "1" {
+		t.Errorf("Query mismatch. Got %q", got)
+	}
+	var body map[string]any
+	if err := call.JSON(&body); err != nil {
+		t.Fatal(err)
+	}
+	metadata, _ := body["metadata"].(map[string]any)
+	if body["model"] != "text-embedding-3-small" || body["dimensions"] != float64(256) || metadata["a"] != "1" || metadata["b"] != "2" {
+		t.Errorf("Unexpected body: %v", body)
+	}
+
+	// Options apply to a single request only.
+	if _, err := client.Embeddings().CreateEmbeddings(&req); err != nil {
+		t.Fatal(err, "CreateEmbeddings error")
+	}
+	call = fs.LastCall(t, http.MethodPost, "/v1/embeddings")
+	if call.Header.Get("X-Gateway") != "default" || call.Header.Get("OpenAI-Project") != "proj_client" || call.Header.Get("Idempotency-Key") != "" {
+		t.Errorf("Request options leaked into a later request: %v", call.Header)
+	}
+}
+
+func TestRequestOptionBaseURL(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := openai_test.NewTestClient(nil, openai.WithBaseURL("http://127.0.0.1:1"))
+	models, err := client.Models().ListModels(option.WithBaseURL(fs.HTTPServer.URL))
+	if err != nil || len(models) == 0 {
+		t.Errorf("ListModels mismatch: %v %v", models, err)
+	}
+	if _, err := client.Models().ListModels(option.WithBaseURL("not a url")); err == nil {
+		t.Error("Expected an error for an invalid base URL")
+	}
+}
+
+func TestRequestOptionTimeout(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	fs.InjectFault(openai_test.Fault{Path: "/v1/models", Latency: 100 * time.Millisecond})
+	client := fs.Client(openai.WithTimeout(20 * time.Millisecond))
+
+	if _, err := client.Models().ListModels(); err == nil {
+		t.Error("Expected the client timeout to expire")
+	}
+	if _, err := client.Models().ListModels(option.WithTimeout(time.Second)); err != nil {
+		t.Error(err, "ListModels error")
+	}
+}
+
+func TestRequestOptionExtraBodyMultipart(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client()
+
+	path := filepath.Join(t.TempDir(), "train.jsonl")
+	if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Files().UploadFile(&openai.UploadFileRequest{File: path, Purpose: "fine-tune"},
+		option.WithExtraBody(map[string]any{"expires_after": 3600}))
+	var apiErr *openai.APIError
+	if err == nil || errors.As(err, &apiErr) {
+		t.Errorf("Expected a client-side error, got %v", err)
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/files", 0)
+}
+
+func TestExtraBodyLargeIntegers(t *testing.T) {
+	fs := openai_test.NewFakeServer()
+	defer fs.Close()
+	client := fs.Client()
+
+	seed := 1<<53 + 1
+	req := openai.CompletionRequest{Model: "gpt-3.5-turbo-instruct", Prompt: []string{"test"}, Seed: &seed}
+	_, err := client.Completions().CreateCompletion(&req,
+		option.WithExtraBody(map[string]any{"metadata": map[string]any{"trace_id": int64(1<<62 + 1)}}))
+	if err != nil {
+		t.Fatal(err, "CreateCompletion error")
+	}
+	call := fs.LastCall(t, http.MethodPost, "/v1/completions")
+	for _, want := range []string{`"seed":9007199254740993`, `"trace_id":4611686018427387905`} {
+		if !strings.Contains(string(call.Body), want) {
+			t.Errorf("Request body %s missing %s", call.Body, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit - snapshot of the x-ratelimit-* headers returned by the API.
+type RateLimit struct {
+	// Maximum number of requests permitted before exhausting the rate limit.
+	LimitRequests int
+	// Remaining number of requests permitted before exhausting the rate limit.
+	RemainingRequests int
+	// Time until the request rate limit resets to its initial state.
+	ResetRequests time.Duration
+	// Maximum number of tokens permitted before exhausting the rate limit.
+	LimitTokens int
+	// Remaining number of tokens permitted before exhausting the rate limit.
+	RemainingTokens int
+	// Time until the token rate limit resets to its initial state.
+	ResetTokens time.Duration
+}
+
+// ResponseMeta - metadata describing the HTTP response to an API call.
+type ResponseMeta struct {
+	// HTTP status code of the final response.
+	StatusCode int
+	// Value of the x-request-id header. Include it when contacting OpenAI support.
+	RequestID string
+	// Time the API spent processing the request, from the openai-processing-ms header.
+	ProcessingTime time.Duration
+	// Rate limit state after the request.
+	RateLimit RateLimit
+	// Raw response headers.
+	Header http.Header
+	// Number of attempts made, including retries.
+	Attempts int
+	// Whether the response was served from the client's Cache without calling the API.
+	Cached bool
+	// Model requested by the attempt which served the response. Set by endpoints with a FallbackPolicy.
+	Model string
+	// Number of fallbacks made before the response, zero when the request's own model served it.
+	Fallbacks int
+}
+
+type responseMetaKey struct{}
+
+// CaptureResponseMeta returns a copy of ctx which makes every API call using it
+// store the metadata of its response into meta.
+//
+//	var meta openai.ResponseMeta
+//	ctx := openai.CaptureResponseMeta(ctx, &meta)
+//	resp, err := client.Chat().CreateChatCompletionWithContext(ctx, req)
+//	log.Printf("request id: %s", meta.RequestID)
+func CaptureResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+func captureResponseMeta(ctx context.Context, meta *ResponseMeta) {
+	if dst, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta); ok && dst != nil {
+		*dst = *meta
+	}
+}
+
+func newResponseMeta(res *http.Response) *ResponseMeta {
+	h := res.Header
+	meta := &ResponseMeta{
+		StatusCode: res.StatusCode,
+		RequestID:  h.Get("x-request-id"),
+		Header:     h.Clone(),
+		RateLimit: RateLimit{
+			LimitRequests:     headerInt(h, "x-ratelimit-limit-requests"),
+			RemainingRequests: headerInt(h, "x-ratelimit-remaining-requests"),
+			ResetRequests:     headerDuration(h, "x-ratelimit-reset-requests"),
+			LimitTokens:       headerInt(h, "x-ratelimit-limit-tokens"),
+			RemainingTokens:   headerInt(h, "x-ratelimit-remaining-tokens"),
+			ResetTokens:       headerDuration(h, "x-ratelimit-reset-tokens"),
+		},
+	}
+	if ms, err := strconv.ParseFloat(h.Get("openai-processing-ms"), 64); err == nil {
+		meta.ProcessingTime = time.Duration(ms * float64(time.Millisecond))
+	}
+	return meta
+}
+
+func headerInt(h http.Header, key string) int {
+	v, _ := strconv.Atoi(h.Get(key))
+	return v
+}
+
+// headerDuration parses durations such as "1s", "6m0s" or "20ms".
+func headerDuration(h http.Header, key string) time.Duration {
+	d, _ := time.ParseDuration(h.Get(key))
+	return d
+}
@@ -0,0 +1,56 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func TestCaptureResponseMeta(t *testing.T) {
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("x-request-id", "req_123")
+		w.Header().Set("openai-processing-ms", "42")
+		w.Header().Set("x-ratelimit-limit-requests", "60")
+		w.Header().Set("x-ratelimit-remaining-requests", "59")
+		w.Header().Set("x-ratelimit-reset-requests", "1s")
+		w.Header().Set("x-ratelimit-limit-tokens", "150000")
+		w.Header().Set("x-ratelimit-remaining-tokens", "149984")
+		w.Header().Set("x-ratelimit-reset-tokens", "6m0s")
+		resBytes, _ := json.Marshal(openai.Model{Object: "model", ID: "testModelID"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	var meta openai.ResponseMeta
+	ctx := openai.CaptureResponseMeta(context.Background(), &meta)
+	_, err := client.Models().RetrieveModelWithContext(ctx, "testModelID")
+	if err != nil {
+		t.Fatal(err, "RetrieveModel error")
+	}
+	if meta.StatusCode != http.StatusOK || meta.RequestID != "req_123" || meta.Attempts != 1 {
+		t.Errorf("Unexpected meta: %+v", meta)
+	}
+	if meta.ProcessingTime != 42*time.Millisecond {
+		t.Errorf("ProcessingTime mismatch. Got %s. Expected %s", meta.ProcessingTime, 42*time.Millisecond)
+	}
+	want := openai.RateLimit{
+		LimitRequests:     60,
+		RemainingRequests: 59,
+		ResetRequests:     time.Second,
+		LimitTokens:       150000,
+		RemainingTokens:   149984,
+		ResetTokens:       6 * time.Minute,
+	}
+	if meta.RateLimit != want {
+		t.Errorf("RateLimit mismatch. Got %+v. Expected %+v", meta.RateLimit, want)
+	}
+}
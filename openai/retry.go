@@ -0,0 +1,170 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy - controls how failed requests are retried by the Client.
+//
+// A request is retried when the response status code is listed in RetryableStatusCodes,
+// or when the transport returns an error accepted by RetryableError.
+// Delays grow exponentially from BaseDelay up to MaxDelay unless the server
+// asks for a specific delay via the Retry-After or retry-after-ms headers.
+// A request for a delay longer than MaxRetryAfter is not retried.
+type RetryPolicy struct {
+	// Total number of attempts, including the first one. Values below 1 are treated as 1.
+	MaxAttempts int
+	// Delay before the first retry.
+	BaseDelay time.Duration
+	// Upper bound for the computed backoff delay.
+	MaxDelay time.Duration
+	// Longest delay requested by the server that is waited for. When the server asks for longer,
+	// the error response is returned immediately. Defaults to MaxDelay, or one minute without MaxDelay.
+	MaxRetryAfter time.Duration
+	// Fraction of the delay, between 0 and 1, that is randomised to spread retries out.
+	Jitter float64
+	// HTTP status codes that should be retried.
+	RetryableStatusCodes []int
+	// Reports whether a transport error should be retried. Defaults to IsRetryableNetworkError.
+	RetryableError func(err error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy recommended for most callers.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:   3,
+		BaseDelay:     500 * time.Millisecond,
+		MaxDelay:      8 * time.Second,
+		MaxRetryAfter: time.Minute,
+		Jitter:        0.25,
+		RetryableStatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusConflict,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableError: IsRetryableNetworkError,
+	}
+}
+
+// IsRetryableNetworkError reports whether err is a transient network failure: a timeout,
+// a refused, reset or broken connection, or a connection closed before the response was complete.
+// Permanent failures such as unknown hosts, invalid certificates and unsupported URL schemes
+// are not retryable, and neither is context cancellation.
+//
+// An io.EOF usually means the server closed a reused keep-alive connection; the Client only
+// retries it when the connection was reused.
+func IsRetryableNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) retryError(err error) bool {
+	if p.RetryableError == nil {
+		return IsRetryableNetworkError(err)
+	}
+	return p.RetryableError(err)
+}
+
+func (p *RetryPolicy) maxRetryAfter() time.Duration {
+	switch {
+	case p.MaxRetryAfter > 0:
+		return p.MaxRetryAfter
+	case p.MaxDelay > 0:
+		return p.MaxDelay
+	}
+	return time.Minute
+}
+
+// delay returns how long to wait before the given retry attempt (starting at 1).
+// It returns false when the server asks for a longer delay than the policy allows.
+func (p *RetryPolicy) delay(retry int, header http.Header) (time.Duration, bool) {
+	if d, ok := retryAfter(header); ok {
+		return d, d <= p.maxRetryAfter()
+	}
+	d := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d), true
+}
+
+// retryAfter parses the retry-after-ms and Retry-After response headers.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+	if v := header.Get("retry-after-ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms >= 0 {
+			return time.Duration(ms * float64(time.Millisecond)), true
+		}
+	}
+	if v := header.Get("Retry-After"); v != "" {
+		if s, err := strconv.ParseFloat(v, 64); err == nil && s >= 0 {
+			return time.Duration(s * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
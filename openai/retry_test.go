@@ -0,0 +1,214 @@
+package openai_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+	"github.com/skyscrapr/openai-sdk-go/openai/test"
+)
+
+func testRetryPolicy() *openai.RetryPolicy {
+	p := openai.DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	p.MaxDelay = 5 * time.Millisecond
+	return p
+}
+
+func TestRetryOnRateLimit(t *testing.T) {
+	calls := 0
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("retry-after-ms", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintln(w, `{"error":{"message":"slow down","type":"requests"}}`)
+			return
+		}
+		resBytes, _ := json.Marshal(openai.Model{Object: "model", ID: "testModelID"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RetryPolicy = testRetryPolicy()
+	model, err := client.Models().RetrieveModel("testModelID")
+	if err != nil {
+		t.Fatal(err, "RetrieveModel error")
+	}
+	if model.ID != "testModelID" || calls != 3 {
+		t.Errorf("Unexpected result: model %q after %d calls", model.ID, calls)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	calls := 0
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintln(w, `{"error":{"message":"slow down","type":"requests"}}`)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RetryPolicy = testRetryPolicy()
+	start := time.Now()
+	_, err := client.Models().RetrieveModel("testModelID")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, openai.ErrRateLimited) || apiErr.Attempts != 1 || calls != 1 {
+		t.Errorf("Unexpected error: %v after %d calls", err, calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Request blocked for %s", elapsed)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	calls := 0
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, `{"error":{"message":"overloaded","type":"server_error"}}`)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RetryPolicy = testRetryPolicy()
+	_, err := client.Models().RetrieveModel("testModelID")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if apiErr.Attempts != 3 || calls != 3 {
+		t.Errorf("Attempts mismatch. Got %d (%d calls). Expected 3", apiErr.Attempts, calls)
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	calls := 0
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/models/testModelID", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"error":{"message":"bad request","type":"invalid_request_error"}}`)
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RetryPolicy = testRetryPolicy()
+	_, err := client.Models().RetrieveModel("testModelID")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Attempts != 1 || calls != 1 {
+		t.Errorf("Unexpected error: %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryUploadFileRewindsBody(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "train.jsonl")
+	if err := os.WriteFile(filePath, []byte(`{"prompt":"p","completion":"c"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	ts := openai_test.NewTestServer()
+	ts.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"completion":"c"`) {
+			t.Errorf("Attempt %d missing file content", calls)
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resBytes, _ := json.Marshal(openai.File{Id: "testFileId", Object: "file"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+	defer ts.HTTPServer.Close()
+
+	client := openai_test.NewTestClient(ts)
+	client.RetryPolicy = testRetryPolicy()
+	file, err := client.Files().UploadFile(&openai.UploadFileRequest{File: filePath, Purpose: "fine-tune"})
+	if err != nil {
+		t.Fatal(err, "UploadFile error")
+	}
+	if file.Id != "testFileId" || calls != 2 {
+		t.Errorf("Unexpected result: file %q after %d calls", file.Id, calls)
+	}
+}
+
+func TestIsRetryableNetworkError(t *testing.T) {
+	urlErr := func(err error) error {
+		return &url.Error{Op: "Post", URL: "https://api.openai.com/v1/chat/completions", Err: err}
+	}
+	dial := func(err error) error {
+		return urlErr(&net.OpError{Op: "dial", Net: "tcp", Err: err})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", urlErr(&net.DNSError{Err: "i/o timeout", Name: "api.openai.com", IsTimeout: true}), true},
+		{"connection refused", dial(os.NewSyscallError("connect", syscall.ECONNREFUSED)), true},
+		{"connection reset", urlErr(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), true},
+		{"broken pipe", urlErr(&net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)}), true},
+		{"unexpected EOF", urlErr(io.ErrUnexpectedEOF), true},
+		{"EOF", urlErr(io.EOF), true},
+		{"no such host", dial(&net.DNSError{Err: "no such host", Name: "api.openai.invalid", IsNotFound: true}), false},
+		{"unknown authority", urlErr(x509.UnknownAuthorityError{}), false},
+		{"certificate verification", urlErr(&tls.CertificateVerificationError{Err: x509.CertificateInvalidError{}}), false},
+		{"unsupported protocol scheme", urlErr(errors.New(`unsupported protocol scheme "ftp"`)), false},
+		{"context canceled", urlErr(context.Canceled), false},
+	}
+	for _, tt := range tests {
+		if got := openai.IsRetryableNetworkError(tt.err); got != tt.want {
+			t.Errorf("IsRetryableNetworkError(%s) = %v, expected %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPermanentNetworkErrors(t *testing.T) {
+	// The test server's certificate is not trusted by the default HTTP client.
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer tlsServer.Close()
+
+	for _, baseURL := range []string{tlsServer.URL, "ftp://api.openai.com"} {
+		client, err := openai.NewClient(openai.WithAPIKey("key"), openai.WithBaseURL(baseURL), openai.WithRetryPolicy(testRetryPolicy()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.Models().ListModels()
+		var reqErr *openai.RequestError
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("Unexpected error for %s: %v", baseURL, err)
+		}
+		if reqErr.Attempts != 1 {
+			t.Errorf("Permanent error for %s retried: %d attempts, %v", baseURL, reqErr.Attempts, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// sseEvent - a server-sent event.
+type sseEvent struct {
+	event string
+	data  []byte
+}
+
+// sseReader reads server-sent events, skipping comments such as keep-alives.
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// next returns the next event with data. Events are dispatched on a blank line,
+// or at the end of the stream when the last one is not terminated.
+func (r *sseReader) next() (*sseEvent, error) {
+	ev := &sseEvent{}
+	var data bytes.Buffer
+	hasData := false
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			if err == io.EOF && hasData {
+				ev.data = data.Bytes()
+				return ev, nil
+			}
+			return nil, err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if hasData {
+				ev.data = data.Bytes()
+				return ev, nil
+			}
+			ev.event = ""
+			continue
+		}
+		if line[0] == ':' {
+			continue
+		}
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			ev.event = string(value)
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package openai_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// RecorderMode - whether a Recorder records or replays interactions.
+type RecorderMode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails on unmatched requests.
+	ModeReplay RecorderMode = iota
+	// ModeRecord forwards requests upstream and records the interactions.
+	ModeRecord
+)
+
+// strippedHeaders are removed from recorded requests and responses.
+var strippedHeaders = []string{"Authorization", "Api-Key", "Proxy-Authorization", "Cookie", "Set-Cookie", "Openai-Organization", "Openai-Project"}
+
+// Cassette - recorded HTTP interactions, stored as JSON.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction - a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+
+	used bool
+}
+
+// RecordedRequest - the parts of a request used for matching.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	// Normalized body: canonical JSON, a canonical summary of multipart forms, or the raw body.
+	Body string `json:"body,omitempty"`
+}
+
+// RecordedResponse - a recorded response. Streams are stored verbatim.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// Set when Body is base64 encoded because the response was not valid UTF-8.
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// Recorder - an http.RoundTripper which records interactions to a cassette file or replays them.
+//
+//	rec, err := openai_test.NewRecorder("testdata/chat.json", openai_test.ModeReplay)
+//	client, err := openai.NewClient(openai.WithHTTPClient(&http.Client{Transport: rec}))
+//	...
+//	err = rec.Stop() // saves the cassette in record mode
+type Recorder struct {
+	// Transport used to reach the upstream in record mode. Defaults to http.DefaultTransport.
+	Upstream http.RoundTripper
+
+	mode     RecorderMode
+	path     string
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// NewRecorder creates a recorder for the cassette at path.
+// In replay mode the cassette must exist; in record mode it is overwritten by Stop.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{mode: mode, path: path, cassette: &Cassette{}}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", path, err)
+		}
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Header: stripHeaders(req.Header),
+		Body:   normalizeBody(req.Header.Get("Content-Type"), reqBody),
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, reqBody, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var match *Interaction
+	for _, i := range r.cassette.Interactions {
+		if i.Request.Method != recorded.Method || i.Request.Path != recorded.Path ||
+			i.Request.Query != recorded.Query || i.Request.Body != recorded.Body {
+			continue
+		}
+		match = i
+		if !i.used {
+			break
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("cassette %s: no recorded interaction matches %s %s?%s with body %q",
+			r.path, recorded.Method, recorded.Path, recorded.Query, recorded.Body)
+	}
+	match.used = true
+	body := []byte(match.Response.Body)
+	if match.Response.Base64 {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(match.Response.Body); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", r.path, err)
+		}
+	}
+	return &http.Response{
+		StatusCode:    match.Response.StatusCode,
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        match.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, reqBody []byte, recorded RecordedRequest) (*http.Response, error) {
+	upstream := r.Upstream
+	if upstream == nil {
+		upstream = http.DefaultTransport
+	}
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(reqBody))
+	res, err := upstream.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	interaction := &Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     stripHeaders(res.Header),
+		},
+	}
+	if utf8.Valid(resBody) {
+		interaction.Response.Body = string(resBody)
+	} else {
+		interaction.Response.Body = base64.StdEncoding.EncodeToString(resBody)
+		interaction.Response.Base64 = true
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+	return res, nil
+}
+
+// Stop saves the cassette when recording. It is a no-op in replay mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// Unused returns the recorded interactions which were never replayed.
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []*Interaction
+	for _, i := range r.cassette.Interactions {
+		if !i.used {
+			unused = append(unused, i)
+		}
+	}
+	return unused
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func stripHeaders(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range strippedHeaders {
+		h.Del(name)
+	}
+	if len(h) == 0 {
+		return nil
+	}
+	return h
+}
+
+// normalizeBody returns a representation of body which is stable across runs:
+// JSON is re-encoded with sorted keys, and multipart forms, whose boundaries are random,
+// are summarised as their field values and file hashes.
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if s, err := normalizeMultipart(body, params["boundary"]); err == nil {
+			return s
+		}
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		// encoding/json sorts map keys, which makes the encoding canonical.
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return string(body)
+}
+
+func normalizeMultipart(body []byte, boundary string) (string, error) {
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	var fields []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			return "", err
+		}
+		if p.FileName() != "" {
+			sum := sha256.Sum256(data)
+			fields = append(fields, fmt.Sprintf("%s=@%s;sha256=%s", p.FormName(), p.FileName(), hex.EncodeToString(sum[:])))
+		} else {
+			fields = append(fields, fmt.Sprintf("%s=%s", p.FormName(), data))
+		}
+	}
+	sort.Strings(fields)
+	return "multipart:" + strings.Join(fields, "&"), nil
+}
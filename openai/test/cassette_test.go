@@ -0,0 +1,79 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassettes", "files.json")
+	uploadPath := filepath.Join(t.TempDir(), "train.jsonl")
+	if err := os.WriteFile(uploadPath, []byte(`{"prompt":"p","completion":"c"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := NewTestServer()
+	ts.RegisterHandler("/v1/files", func(w http.ResponseWriter, _ *http.Request) {
+		resBytes, _ := json.Marshal(openai.File{Id: "testFileId", Object: "file", Filename: "train.jsonl"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		resBytes, _ := json.Marshal(openai.EmbeddingsResponse{Object: "list", Model: "testModelID"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	ts.HTTPServer.Start()
+
+	run := func(rec *Recorder, baseURL string) {
+		t.Helper()
+		client := NewTestClient(nil, openai.WithBaseURL(baseURL), openai.WithHTTPClient(&http.Client{Transport: rec}))
+		file, err := client.Files().UploadFile(&openai.UploadFileRequest{File: uploadPath, Purpose: "fine-tune"})
+		if err != nil || file.Id != "testFileId" {
+			t.Fatalf("UploadFile: %v %v", file, err)
+		}
+		resp, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "testModelID", Input: "test"})
+		if err != nil || resp.Model != "testModelID" {
+			t.Fatalf("CreateEmbeddings: %v %v", resp, err)
+		}
+	}
+
+	rec, err := NewRecorder(cassettePath, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(rec, ts.HTTPServer.URL)
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	ts.HTTPServer.Close()
+
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), GetTestAuthToken()) {
+		t.Error("Cassette contains the API key")
+	}
+
+	rec, err = NewRecorder(cassettePath, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The upstream is gone, so every response must come from the cassette.
+	run(rec, "http://127.0.0.1:1")
+	if unused := rec.Unused(); len(unused) != 0 {
+		t.Errorf("Unused interactions: %d", len(unused))
+	}
+
+	client := NewTestClient(nil, openai.WithBaseURL("http://127.0.0.1:1"), openai.WithHTTPClient(&http.Client{Transport: rec}))
+	_, err = client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "testModelID", Input: "different"})
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction matches") {
+		t.Errorf("Expected an unmatched request error, got %v", err)
+	}
+}
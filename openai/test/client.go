@@ -2,7 +2,6 @@ package openai_test
 
 import (
 	"github.com/skyscrapr/openai-sdk-go/openai"
-	"net/url"
 )
 
 const test_api_key = "this-is-my-secure-apikey-do-not-steal!!"
@@ -11,10 +10,14 @@ func GetTestAuthToken() string {
 	return test_api_key
 }
 
-func NewTestClient(ts *TestServer) *openai.Client {
-	client := openai.NewClient(test_api_key)
+func NewTestClient(ts *TestServer, opts ...openai.ClientOption) *openai.Client {
+	opts = append([]openai.ClientOption{openai.WithAPIKey(test_api_key)}, opts...)
 	if ts != nil {
-		client.BaseURL, _ = url.Parse(ts.HTTPServer.URL)
+		opts = append(opts, openai.WithBaseURL(ts.HTTPServer.URL))
+	}
+	client, err := openai.NewClient(opts...)
+	if err != nil {
+		panic(err)
 	}
 	return client
 }
@@ -0,0 +1,899 @@
+package openai_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+)
+
+// FakeServer - a stateful in-memory OpenAI API for end to end tests.
+//
+// Files, vector stores, assistants, assistant files, fine-tuning jobs and models are
+// kept in memory and support create, list (with pagination), retrieve, modify and delete.
+// Chat completions, completions and embeddings return canned responses unless
+// responses are scripted with Script or ScriptFunc. Chat completions requested with
+// stream set are sent as server-sent events, one chunk per word of each choice.
+// Faults can be injected with InjectFault, and every request is recorded for assertions.
+//
+//	fs := openai_test.NewFakeServer()
+//	defer fs.Close()
+//	client := fs.Client()
+type FakeServer struct {
+	HTTPServer *httptest.Server
+	// Clock used for created_at timestamps. Defaults to time.Now.
+	Now func() time.Time
+
+	mu             sync.Mutex
+	seq            int
+	collections    map[string]*fakeCollection
+	fileContent    map[string][]byte
+	jobEvents      map[string]*fakeCollection
+	assistantFiles map[string]*fakeCollection
+	scripts        map[string]*fakeScript
+	faults         []*Fault
+	calls          []Call
+}
+
+// Call - a request received by a FakeServer.
+type Call struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the body of the call into v.
+func (c Call) JSON(v any) error {
+	return json.Unmarshal(c.Body, v)
+}
+
+// Fault - a failure injected into FakeServer responses.
+type Fault struct {
+	// Path the fault applies to, e.g. "/v1/chat/completions". Empty matches every path.
+	Path string
+	// Delay before responding.
+	Latency time.Duration
+	// Respond with this status code and an OpenAI error body, e.g. http.StatusTooManyRequests.
+	StatusCode int
+	// Value of the Retry-After header sent with StatusCode.
+	RetryAfter time.Duration
+	// Respond with 200 OK and a body which is not valid JSON.
+	Malformed bool
+	// Number of requests the fault applies to. Zero means every request.
+	Times int
+
+	hits int
+}
+
+// Script handler for a model endpoint. It receives the decoded request body and
+// returns the response, or an *openai.APIError to respond with an error.
+type ScriptFunc func(req map[string]any) (any, error)
+
+type fakeScript struct {
+	responses []any
+	fn        ScriptFunc
+}
+
+// Resources created by the FakeServer, by collection name.
+const (
+	fakeFiles        = "files"
+	fakeVectorStores = "vector_stores"
+	fakeAssistants   = "assistants"
+	fakeJobs         = "fine_tuning/jobs"
+	fakeModels       = "models"
+)
+
+// NewFakeServer starts a FakeServer with a few models available.
+func NewFakeServer() *FakeServer {
+	s := &FakeServer{
+		collections: map[string]*fakeCollection{
+			fakeFiles:        {},
+			fakeVectorStores: {},
+			fakeAssistants:   {},
+			fakeJobs:         {},
+			fakeModels:       {},
+		},
+		fileContent:    make(map[string][]byte),
+		jobEvents:      make(map[string]*fakeCollection),
+		assistantFiles: make(map[string]*fakeCollection),
+		scripts:        make(map[string]*fakeScript),
+	}
+	for _, id := range []string{"gpt-4o", "gpt-4o-mini", "gpt-3.5-turbo-instruct", "text-embedding-3-small"} {
+		s.AddModel(openai.Model{ID: id, Object: "model", OwnedBy: "openai"})
+	}
+	s.HTTPServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts down the server.
+func (s *FakeServer) Close() {
+	s.HTTPServer.Close()
+}
+
+// Client returns a client configured to call the server.
+func (s *FakeServer) Client(opts ...openai.ClientOption) *openai.Client {
+	opts = append([]openai.ClientOption{openai.WithBaseURL(s.HTTPServer.URL)}, opts...)
+	return NewTestClient(nil, opts...)
+}
+
+// AddModel makes a model available from the models endpoint.
+func (s *FakeServer) AddModel(model openai.Model) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if model.Object == "" {
+		model.Object = "model"
+	}
+	if model.CreatedAt == 0 {
+		model.CreatedAt = s.now()
+	}
+	obj := toObject(model)
+	obj["id"] = model.ID
+	s.collections[fakeModels].put(obj)
+}
+
+// Script queues responses for a model endpoint path, e.g. "/v1/chat/completions".
+// Responses are returned in order and the last one is repeated once the queue is exhausted.
+// A response may be an *openai.APIError to respond with an error.
+func (s *FakeServer) Script(path string, responses ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[path] = &fakeScript{responses: responses}
+}
+
+// ScriptFunc responds to requests for a model endpoint path with fn.
+func (s *FakeServer) ScriptFunc(path string, fn ScriptFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[path] = &fakeScript{fn: fn}
+}
+
+// InjectFault adds a fault. Faults are applied in the order they were added.
+func (s *FakeServer) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every injected fault.
+func (s *FakeServer) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Calls returns the requests received so far.
+func (s *FakeServer) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the requests received for method and path.
+func (s *FakeServer) CallsTo(method string, path string) []Call {
+	var calls []Call
+	for _, c := range s.Calls() {
+		if c.Method == method && c.Path == path {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// LastCall returns the most recent request for method and path, failing the test if there is none.
+func (s *FakeServer) LastCall(t testing.TB, method string, path string) Call {
+	t.Helper()
+	calls := s.CallsTo(method, path)
+	if len(calls) == 0 {
+		t.Fatalf("no request received for %s %s", method, path)
+		return Call{}
+	}
+	return calls[len(calls)-1]
+}
+
+// AssertCalled fails the test unless exactly times requests were received for method and path.
+func (s *FakeServer) AssertCalled(t testing.TB, method string, path string, times int) {
+	t.Helper()
+	if got := len(s.CallsTo(method, path)); got != times {
+		t.Errorf("%s %s called %d times. Expected %d", method, path, got, times)
+	}
+}
+
+func (s *FakeServer) now() int64 {
+	if s.Now != nil {
+		return s.Now().Unix()
+	}
+	return time.Now().Unix()
+}
+
+// newID returns a unique object ID with prefix. The caller must hold s.mu.
+func (s *FakeServer) newID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%06d", prefix, s.seq)
+}
+
+func (s *FakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	fault := s.fault(r.URL.Path)
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.StatusCode != 0 {
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(fault.RetryAfter.Seconds()))))
+			}
+			writeError(w, fault.StatusCode, "fault_injected", "injected fault")
+			return
+		}
+		if fault.Malformed {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"object": "list", "data": [`)
+			return
+		}
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+GetTestAuthToken() {
+		writeError(w, http.StatusUnauthorized, "invalid_api_key", "Incorrect API key provided.")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	w.Header().Set("x-request-id", "req_"+strconv.Itoa(len(s.Calls())))
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/v1/")
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "Invalid URL ("+r.Method+" "+r.URL.Path+")")
+		return
+	}
+	switch {
+	case path == "chat/completions", path == "completions", path == "embeddings":
+		s.serveScripted(w, r.URL.Path, path, body)
+	case path == fakeFiles || strings.HasPrefix(path, fakeFiles+"/"):
+		s.serveFiles(w, r, strings.TrimPrefix(strings.TrimPrefix(path, fakeFiles), "/"))
+	case path == fakeJobs || strings.HasPrefix(path, fakeJobs+"/"):
+		s.serveJobs(w, r, strings.TrimPrefix(strings.TrimPrefix(path, fakeJobs), "/"), body)
+	default:
+		name, id, _ := strings.Cut(path, "/")
+		assistantID, sub, nested := strings.Cut(id, "/")
+		switch {
+		case name == fakeAssistants && nested:
+			s.serveAssistantFiles(w, r, assistantID, sub, body)
+		case name == fakeVectorStores, name == fakeAssistants, name == fakeModels:
+			s.serveCollection(w, r, name, id, body)
+		default:
+			writeError(w, http.StatusNotFound, "not_found", "Invalid URL ("+r.Method+" "+r.URL.Path+")")
+		}
+	}
+}
+
+// fault returns the fault to apply to a request for path. The caller must hold s.mu.
+func (s *FakeServer) fault(path string) *Fault {
+	for _, f := range s.faults {
+		if f.Path != "" && f.Path != path {
+			continue
+		}
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		f.hits++
+		return f
+	}
+	return nil
+}
+
+// serveCollection handles the CRUD endpoints of vector stores, assistants and models.
+func (s *FakeServer) serveCollection(w http.ResponseWriter, r *http.Request, name string, id string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collections[name]
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		if name == fakeModels {
+			writeJSON(w, map[string]any{"object": "list", "data": c.items})
+			return
+		}
+		writeList(w, r, c)
+	case id == "" && r.Method == http.MethodPost && name != fakeModels:
+		var req map[string]any
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body")
+			return
+		}
+		obj := s.create(name, req)
+		if obj == nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid "+name+" request")
+			return
+		}
+		c.put(obj)
+		writeJSON(w, obj)
+	case id != "":
+		obj := c.get(id)
+		if obj == nil {
+			writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No such %s: '%s'", strings.TrimSuffix(name, "s"), id))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, obj)
+		case http.MethodPost:
+			var req map[string]any
+			if err := json.Unmarshal(body, &req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body")
+				return
+			}
+			for k, v := range req {
+				if k != "id" && k != "object" && k != "created_at" {
+					obj[k] = v
+				}
+			}
+			writeJSON(w, obj)
+		case http.MethodDelete:
+			c.delete(id)
+			writeJSON(w, map[string]any{"id": id, "object": obj["object"].(string) + ".deleted", "deleted": true})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+	}
+}
+
+// create builds a new vector store or assistant from a request. The caller must hold s.mu.
+func (s *FakeServer) create(name string, req map[string]any) map[string]any {
+	obj := req
+	switch name {
+	case fakeVectorStores:
+		fileIDs, _ := req["file_ids"].([]any)
+		delete(obj, "file_ids")
+		obj["id"] = s.newID("vs")
+		obj["object"] = "vector_store"
+		obj["status"] = "completed"
+		obj["usage_bytes"] = 0
+		obj["last_active_at"] = s.now()
+		obj["file_counts"] = map[string]any{"in_progress": 0, "completed": len(fileIDs), "failed": 0, "cancelled": 0, "total": len(fileIDs)}
+	case fakeAssistants:
+		if model, _ := req["model"].(string); model == "" {
+			return nil
+		}
+		obj["id"] = s.newID("asst")
+		obj["object"] = "assistant"
+	}
+	obj["created_at"] = s.now()
+	return obj
+}
+
+func (s *FakeServer) serveFiles(w http.ResponseWriter, r *http.Request, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collections[fakeFiles]
+	id, sub, _ := strings.Cut(path, "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		writeList(w, r, c.filter(func(obj map[string]any) bool {
+			purpose := r.URL.Query().Get("purpose")
+			return purpose == "" || obj["purpose"] == purpose
+		}))
+	case id == "" && r.Method == http.MethodPost:
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "missing file: "+err.Error())
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		obj := map[string]any{
+			"id":         s.newID("file"),
+			"object":     "file",
+			"bytes":      len(data),
+			"created_at": s.now(),
+			"filename":   header.Filename,
+			"purpose":    r.FormValue("purpose"),
+		}
+		c.put(obj)
+		s.fileContent[obj["id"].(string)] = data
+		writeJSON(w, obj)
+	default:
+		obj := c.get(id)
+		if obj == nil {
+			writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No such File object: %s", id))
+			return
+		}
+		switch {
+		case sub == "content" && r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(s.fileContent[id])
+		case sub == "" && r.Method == http.MethodGet:
+			writeJSON(w, obj)
+		case sub == "" && r.Method == http.MethodDelete:
+			c.delete(id)
+			delete(s.fileContent, id)
+			writeJSON(w, map[string]any{"id": id, "object": "file", "deleted": true})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		}
+	}
+}
+
+func (s *FakeServer) serveJobs(w http.ResponseWriter, r *http.Request, path string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collections[fakeJobs]
+	id, sub, _ := strings.Cut(path, "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		writeList(w, r, c)
+	case id == "" && r.Method == http.MethodPost:
+		var req map[string]any
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body")
+			return
+		}
+		trainingFile, _ := req["training_file"].(string)
+		if s.collections[fakeFiles].get(trainingFile) == nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid training_file: %s", trainingFile))
+			return
+		}
+		obj := map[string]any{
+			"id":              s.newID("ftjob"),
+			"object":          "fine_tuning.job",
+			"created_at":      s.now(),
+			"model":           req["model"],
+			"training_file":   trainingFile,
+			"validation_file": req["validation_file"],
+			"status":          "queued",
+			"result_files":    []string{},
+			"hyperparams":     map[string]any{"n_epochs": 3},
+		}
+		c.put(obj)
+		s.addJobEvent(obj["id"].(string), "Created fine-tuning job")
+		writeJSON(w, obj)
+	default:
+		obj := c.get(id)
+		if obj == nil {
+			writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No such fine-tuning job: %s", id))
+			return
+		}
+		switch {
+		case sub == "" && r.Method == http.MethodGet:
+			writeJSON(w, obj)
+		case sub == "cancel" && r.Method == http.MethodPost:
+			obj["status"] = "cancelled"
+			obj["finished_at"] = s.now()
+			s.addJobEvent(id, "Fine-tuning job cancelled")
+			writeJSON(w, obj)
+		case sub == "events" && r.Method == http.MethodGet:
+			writeList(w, r, s.jobEvents[id])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		}
+	}
+}
+
+// serveAssistantFiles handles the files attached to an assistant.
+func (s *FakeServer) serveAssistantFiles(w http.ResponseWriter, r *http.Request, assistantID string, path string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.collections[fakeAssistants].get(assistantID) == nil {
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No such assistant: '%s'", assistantID))
+		return
+	}
+	sub, id, _ := strings.Cut(path, "/")
+	if sub != "files" {
+		writeError(w, http.StatusNotFound, "not_found", "Invalid URL ("+r.Method+" "+r.URL.Path+")")
+		return
+	}
+	c, ok := s.assistantFiles[assistantID]
+	if !ok {
+		c = &fakeCollection{}
+		s.assistantFiles[assistantID] = c
+	}
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		writeList(w, r, c)
+	case id == "" && r.Method == http.MethodPost:
+		var req struct {
+			FileID string `json:"file_id"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body")
+			return
+		}
+		if s.collections[fakeFiles].get(req.FileID) == nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid file_id: %s", req.FileID))
+			return
+		}
+		obj := map[string]any{
+			"id":           req.FileID,
+			"object":       "assistant.file",
+			"created_at":   s.now(),
+			"assistant_id": assistantID,
+		}
+		c.put(obj)
+		writeJSON(w, obj)
+	default:
+		obj := c.get(id)
+		if obj == nil {
+			writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No such assistant file: '%s'", id))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, obj)
+		case http.MethodDelete:
+			c.delete(id)
+			writeJSON(w, map[string]any{"id": id, "object": "assistant.file.deleted", "deleted": true})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		}
+	}
+}
+
+// SetJobStatus moves a fine-tuning job to status, e.g. "succeeded", recording an event.
+func (s *FakeServer) SetJobStatus(id string, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.collections[fakeJobs].get(id)
+	if obj == nil {
+		return fmt.Errorf("no such fine-tuning job: %s", id)
+	}
+	obj["status"] = status
+	if status == "succeeded" {
+		obj["fine_tuned_model"] = fmt.Sprintf("ft:%s:fake::%s", obj["model"], id)
+		obj["finished_at"] = s.now()
+	}
+	s.addJobEvent(id, "Fine-tuning job "+status)
+	return nil
+}
+
+// addJobEvent records an event for a fine-tuning job. The caller must hold s.mu.
+func (s *FakeServer) addJobEvent(jobID string, message string) {
+	events, ok := s.jobEvents[jobID]
+	if !ok {
+		events = &fakeCollection{}
+		s.jobEvents[jobID] = events
+	}
+	events.put(map[string]any{
+		"id":         s.newID("ftevent"),
+		"object":     "fine_tuning.job.event",
+		"created_at": s.now(),
+		"level":      "info",
+		"message":    message,
+		"type":       "message",
+	})
+}
+
+func (s *FakeServer) serveScripted(w http.ResponseWriter, fullPath string, path string, body []byte) {
+	var req map[string]any
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body")
+		return
+	}
+	s.mu.Lock()
+	script := s.scripts[fullPath]
+	var resp any
+	var err error
+	switch {
+	case script == nil:
+		resp = s.defaultResponse(path, req)
+	case script.fn != nil:
+		s.mu.Unlock()
+		resp, err = script.fn(req)
+		s.mu.Lock()
+	case len(script.responses) > 0:
+		resp = script.responses[0]
+		if len(script.responses) > 1 {
+			script.responses = script.responses[1:]
+		}
+	}
+	s.mu.Unlock()
+	if apiErr, ok := resp.(*openai.APIError); ok {
+		err = apiErr
+	}
+	if err != nil {
+		if apiErr, ok := err.(*openai.APIError); ok {
+			status := apiErr.HTTPStatusCode
+			if status == 0 {
+				status = http.StatusBadRequest
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]any{"error": apiErr})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	if stream, _ := req["stream"].(bool); stream && path == "chat/completions" {
+		includeUsage := false
+		if opts, ok := req["stream_options"].(map[string]any); ok {
+			includeUsage, _ = opts["include_usage"].(bool)
+		}
+		writeChatStream(w, resp, includeUsage)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// writeChatStream sends a chat completion response as a stream of chunks.
+func writeChatStream(w http.ResponseWriter, resp any, includeUsage bool) {
+	var completion struct {
+		ID      string          `json:"id"`
+		Created int64           `json:"created"`
+		Model   string          `json:"model"`
+		Usage   json.RawMessage `json:"usage"`
+		Choices []struct {
+			Index   int `json:"index"`
+			Message struct {
+				Role      string                `json:"role"`
+				Content   string                `json:"content"`
+				ToolCalls []openai.ChatToolCall `json:"tool_calls"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	data, _ := json.Marshal(resp)
+	json.Unmarshal(data, &completion)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	send := func(choices []any, usage json.RawMessage) {
+		chunk := map[string]any{
+			"id":      completion.ID,
+			"object":  "chat.completion.chunk",
+			"created": completion.Created,
+			"model":   completion.Model,
+			"choices": choices,
+		}
+		if usage != nil {
+			chunk["usage"] = usage
+		}
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	for _, c := range completion.Choices {
+		send([]any{map[string]any{"index": c.Index, "delta": map[string]any{"role": c.Message.Role, "content": ""}}}, nil)
+		for _, word := range strings.SplitAfter(c.Message.Content, " ") {
+			if word != "" {
+				send([]any{map[string]any{"index": c.Index, "delta": map[string]any{"content": word}}}, nil)
+			}
+		}
+		for i, call := range c.Message.ToolCalls {
+			fn := map[string]any{"name": call.Function.Name, "arguments": ""}
+			delta := map[string]any{"index": i, "id": call.ID, "type": call.Type, "function": fn}
+			send([]any{map[string]any{"index": c.Index, "delta": map[string]any{"tool_calls": []any{delta}}}}, nil)
+			// Arguments arrive in small fragments, as with the real API.
+			for args := call.Function.Arguments; args != ""; {
+				n := min(8, len(args))
+				delta := map[string]any{"index": i, "function": map[string]any{"arguments": args[:n]}}
+				send([]any{map[string]any{"index": c.Index, "delta": map[string]any{"tool_calls": []any{delta}}}}, nil)
+				args = args[n:]
+			}
+		}
+		send([]any{map[string]any{"index": c.Index, "delta": map[string]any{}, "finish_reason": c.FinishReason}}, nil)
+	}
+	if includeUsage && completion.Usage != nil {
+		send([]any{}, completion.Usage)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// defaultResponse returns a canned response for a model endpoint. The caller must hold s.mu.
+func (s *FakeServer) defaultResponse(path string, req map[string]any) any {
+	model, _ := req["model"].(string)
+	promptTokens := (len(fmt.Sprint(req["messages"], req["prompt"], req["input"])) + 3) / 4
+	const text = "This is a fake response."
+	usage := map[string]any{"prompt_tokens": promptTokens, "completion_tokens": 6, "total_tokens": promptTokens + 6}
+	switch path {
+	case "chat/completions":
+		return map[string]any{
+			"id":      s.newID("chatcmpl"),
+			"object":  "chat.completion",
+			"created": s.now(),
+			"model":   model,
+			"choices": []any{map[string]any{
+				"index":         0,
+				"message":       map[string]any{"role": "assistant", "content": text},
+				"finish_reason": "stop",
+			}},
+			"usage": usage,
+		}
+	case "completions":
+		return map[string]any{
+			"id":      s.newID("cmpl"),
+			"object":  "text_completion",
+			"created": s.now(),
+			"model":   model,
+			"choices": []any{map[string]any{"index": 0, "text": text, "finish_reason": "stop"}},
+			"usage":   usage,
+		}
+	default:
+		var inputs []string
+		switch input := req["input"].(type) {
+		case string:
+			inputs = []string{input}
+		case []any:
+			for _, v := range input {
+				inputs = append(inputs, fmt.Sprint(v))
+			}
+		}
+		data := make([]any, len(inputs))
+		for i, input := range inputs {
+			data[i] = map[string]any{"object": "embedding", "index": i, "embedding": fakeEmbedding(input)}
+		}
+		return map[string]any{
+			"object": "list",
+			"model":  model,
+			"data":   data,
+			"usage":  map[string]any{"prompt_tokens": promptTokens, "total_tokens": promptTokens},
+		}
+	}
+}
+
+// fakeEmbedding returns a deterministic unit vector derived from input.
+func fakeEmbedding(input string) []float64 {
+	sum := sha256.Sum256([]byte(input))
+	v := make([]float64, 8)
+	var norm float64
+	for i := range v {
+		v[i] = float64(int16(binary.BigEndian.Uint16(sum[i*2:]))) / math.MaxInt16
+		norm += v[i] * v[i]
+	}
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+// fakeCollection - objects in creation order.
+type fakeCollection struct {
+	items []map[string]any
+}
+
+func (c *fakeCollection) put(obj map[string]any) {
+	for i, item := range c.items {
+		if item["id"] == obj["id"] {
+			c.items[i] = obj
+			return
+		}
+	}
+	c.items = append(c.items, obj)
+}
+
+func (c *fakeCollection) get(id string) map[string]any {
+	for _, item := range c.items {
+		if item["id"] == id {
+			return item
+		}
+	}
+	return nil
+}
+
+func (c *fakeCollection) delete(id string) {
+	for i, item := range c.items {
+		if item["id"] == id {
+			c.items = append(c.items[:i], c.items[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *fakeCollection) filter(keep func(map[string]any) bool) *fakeCollection {
+	filtered := &fakeCollection{}
+	for _, item := range c.items {
+		if keep(item) {
+			filtered.items = append(filtered.items, item)
+		}
+	}
+	return filtered
+}
+
+// writeList writes a page of c according to the after, before, limit and order query parameters.
+// Objects are listed newest first unless order=asc.
+func writeList(w http.ResponseWriter, r *http.Request, c *fakeCollection) {
+	q := r.URL.Query()
+	items := []map[string]any{}
+	if c != nil {
+		items = append(items, c.items...)
+	}
+	if q.Get("order") != "asc" {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	limit := 20
+	if l := q.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > 100 {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "limit must be between 1 and 100")
+			return
+		}
+		limit = n
+	}
+	start, end := 0, len(items)
+	for i, item := range items {
+		if id := q.Get("after"); id != "" && item["id"] == id {
+			start = i + 1
+		}
+		if id := q.Get("before"); id != "" && item["id"] == id {
+			end = i
+		}
+	}
+	if start > end {
+		start = end
+	}
+	page := items[start:end]
+	hasMore := false
+	if len(page) > limit {
+		if q.Get("before") != "" {
+			page = page[len(page)-limit:]
+		} else {
+			page = page[:limit]
+		}
+		hasMore = true
+	}
+	list := map[string]any{"object": "list", "data": page, "has_more": hasMore, "first_id": nil, "last_id": nil}
+	if len(page) > 0 {
+		list["first_id"] = page[0]["id"]
+		list["last_id"] = page[len(page)-1]["id"]
+	}
+	writeJSON(w, list)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	errType := "invalid_request_error"
+	switch {
+	case status == http.StatusTooManyRequests:
+		errType = "rate_limit_exceeded"
+	case status >= 500:
+		errType = "server_error"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{
+		"message": message,
+		"type":    errType,
+		"param":   nil,
+		"code":    code,
+	}})
+}
+
+// toObject converts v to a JSON object.
+func toObject(v any) map[string]any {
+	data, _ := json.Marshal(v)
+	var obj map[string]any
+	json.Unmarshal(data, &obj)
+	return obj
+}
@@ -0,0 +1,199 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skyscrapr/openai-sdk-go/openai"
+)
+
+func TestFakeServerFilesAndFineTuning(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+	client := fs.Client()
+
+	path := filepath.Join(t.TempDir(), "train.jsonl")
+	if err := os.WriteFile(path, []byte(`{"prompt":"p","completion":"c"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := client.Files().UploadFile(&openai.UploadFileRequest{File: path, Purpose: "fine-tune"})
+	if err != nil {
+		t.Fatal(err, "UploadFile error")
+	}
+	content, err := client.Files().RetrieveFileContent(file.Id)
+	if err != nil || *content != `{"prompt":"p","completion":"c"}` {
+		t.Errorf("RetrieveFileContent mismatch: %v %v", content, err)
+	}
+
+	job, err := client.FineTuning().CreateFineTuningJob(&openai.CreateFineTuningJobRequest{TrainingFile: file.Id, Model: "gpt-4o-mini"})
+	if err != nil {
+		t.Fatal(err, "CreateFineTuningJob error")
+	}
+	if err := fs.SetJobStatus(job.Id, "succeeded"); err != nil {
+		t.Fatal(err)
+	}
+	job, err = client.FineTuning().GetFineTuningJob(job.Id)
+	if err != nil || job.Status != "succeeded" || job.FineTunedModel == "" {
+		t.Errorf("GetFineTuningJob mismatch: %+v %v", job, err)
+	}
+	events, err := client.FineTuning().ListFineTuningEvents(job.Id, nil, nil)
+	if err != nil || len(events) != 2 {
+		t.Errorf("ListFineTuningEvents mismatch: %d %v", len(events), err)
+	}
+
+	_, err = client.FineTuning().CreateFineTuningJob(&openai.CreateFineTuningJobRequest{TrainingFile: "file-missing", Model: "gpt-4o-mini"})
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a bad request error, got %v", err)
+	}
+
+	if deleted, err := client.Files().DeleteFile(file.Id); err != nil || !deleted {
+		t.Errorf("DeleteFile mismatch: %v %v", deleted, err)
+	}
+	if _, err := client.Files().RetrieveFile(file.Id); !errors.Is(err, openai.ErrNotFound) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrNotFound)
+	}
+}
+
+func TestFakeServerPagination(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+	client := fs.Client()
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		name := "assistant"
+		a, err := client.Assistants().CreateAssistant(&openai.AssistantRequest{Model: "gpt-4o", Name: &name})
+		if err != nil {
+			t.Fatal(err, "CreateAssistant error")
+		}
+		ids = append(ids, a.Id)
+	}
+	limit := 2
+	page, err := client.Assistants().ListAssistants(nil, &limit)
+	if err != nil || len(page) != 2 || page[0].Id != ids[4] {
+		t.Fatalf("ListAssistants mismatch: %+v %v", page, err)
+	}
+	page, err = client.Assistants().ListAssistants(&page[1].Id, &limit)
+	if err != nil || len(page) != 2 || page[0].Id != ids[2] {
+		t.Errorf("ListAssistants mismatch: %+v %v", page, err)
+	}
+	fs.AssertCalled(t, http.MethodGet, "/v1/assistants", 2)
+	if after := fs.LastCall(t, http.MethodGet, "/v1/assistants").Query.Get("after"); after != ids[3] {
+		t.Errorf("after mismatch. Got %s. Expected %s", after, ids[3])
+	}
+
+	models, err := client.Models().ListModels()
+	if err != nil || len(models) == 0 {
+		t.Errorf("ListModels mismatch: %v %v", models, err)
+	}
+}
+
+func TestFakeServerAssistantFiles(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+	client := fs.Client()
+
+	assistant, err := client.Assistants().CreateAssistant(&openai.AssistantRequest{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err, "CreateAssistant error")
+	}
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("notes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for i := 0; i < 5; i++ {
+		file, err := client.Files().UploadFile(&openai.UploadFileRequest{File: path, Purpose: "assistants"})
+		if err != nil {
+			t.Fatal(err, "UploadFile error")
+		}
+		af, err := client.Assistants().CreateAssistantFile(assistant.Id, file.Id)
+		if err != nil || af.Id != file.Id || af.AssistantId != assistant.Id {
+			t.Fatalf("CreateAssistantFile mismatch: %+v %v", af, err)
+		}
+		ids = append(ids, af.Id)
+	}
+
+	page, err := client.Assistants().ListAssistantFilesPage(context.Background(), assistant.Id, &openai.ListParams{Limit: 2})
+	if err != nil || len(page.Data) != 2 || page.FirstID != ids[4] || page.LastID != ids[3] || !page.HasMore {
+		t.Fatalf("ListAssistantFilesPage mismatch: %+v %v", page, err)
+	}
+	page, err = client.Assistants().ListAssistantFilesPage(context.Background(), assistant.Id, &openai.ListParams{Limit: 2, After: page.LastID})
+	if err != nil || page.FirstID != ids[2] || page.LastID != ids[1] || !page.HasMore {
+		t.Errorf("ListAssistantFilesPage mismatch: %+v %v", page, err)
+	}
+
+	it := client.Assistants().ListAssistantFilesAutoPaging(context.Background(), assistant.Id, &openai.ListParams{Limit: 2, Order: "asc"})
+	var got []string
+	for it.Next() {
+		got = append(got, it.Value().Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err, "ListAssistantFilesAutoPaging error")
+	}
+	if fmt.Sprint(got) != fmt.Sprint(ids) {
+		t.Errorf("Iterated IDs mismatch. Got %v. Expected %v", got, ids)
+	}
+	fs.AssertCalled(t, http.MethodGet, "/v1/assistants/"+assistant.Id+"/files", 5)
+
+	if af, err := client.Assistants().RetrieveAssistantFile(assistant.Id, ids[0]); err != nil || af.Id != ids[0] {
+		t.Errorf("RetrieveAssistantFile mismatch: %+v %v", af, err)
+	}
+	if deleted, err := client.Assistants().DeleteAssistantFile(assistant.Id, ids[0]); err != nil || !deleted {
+		t.Errorf("DeleteAssistantFile mismatch: %v %v", deleted, err)
+	}
+	if _, err := client.Assistants().RetrieveAssistantFile(assistant.Id, ids[0]); !errors.Is(err, openai.ErrNotFound) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrNotFound)
+	}
+	if _, err := client.Assistants().ListAssistantFilesPage(context.Background(), "asst-missing", nil); !errors.Is(err, openai.ErrNotFound) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrNotFound)
+	}
+}
+
+func TestFakeServerScriptsAndFaults(t *testing.T) {
+	fs := NewFakeServer()
+	defer fs.Close()
+	client := fs.Client(openai.WithRetryPolicy(&openai.RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		MaxDelay:             time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests},
+	}))
+
+	resp, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"})
+	if err != nil || len(resp.Data) != 1 || len(resp.Data[0].Embedding) == 0 {
+		t.Fatalf("CreateEmbeddings mismatch: %+v %v", resp, err)
+	}
+
+	fs.Script("/v1/completions",
+		map[string]any{"object": "text_completion", "model": "gpt-3.5-turbo-instruct", "choices": []any{map[string]any{"text": "first"}}},
+		&openai.APIError{Message: "scripted failure", HTTPStatusCode: http.StatusInternalServerError},
+	)
+	completion, err := client.Completions().CreateCompletion(&openai.CompletionRequest{Model: "gpt-3.5-turbo-instruct"})
+	if err != nil || completion.Choices[0].Text != "first" {
+		t.Errorf("CreateCompletion mismatch: %+v %v", completion, err)
+	}
+	if _, err := client.Completions().CreateCompletion(&openai.CompletionRequest{Model: "gpt-3.5-turbo-instruct"}); !errors.Is(err, openai.ErrServerError) {
+		t.Errorf("Unexpected error: %v, expected: %v", err, openai.ErrServerError)
+	}
+
+	fs.InjectFault(Fault{Path: "/v1/embeddings", StatusCode: http.StatusTooManyRequests, Times: 2})
+	if _, err := client.Embeddings().CreateEmbeddings(&openai.EmbeddingsRequest{Model: "text-embedding-3-small", Input: "test"}); err != nil {
+		t.Error(err, "CreateEmbeddings error")
+	}
+	fs.AssertCalled(t, http.MethodPost, "/v1/embeddings", 4)
+
+	fs.InjectFault(Fault{Malformed: true})
+	_, err = client.Models().ListModels()
+	var decodeErr *openai.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("Expected a decode error, got %v", err)
+	}
+}
@@ -1,7 +1,10 @@
 package openai
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
+
+	"github.com/skyscrapr/openai-sdk-go/openai/option"
 )
 
 const VectorStoresEndpointPath = "/vector_stores/"
@@ -43,21 +46,57 @@ type VectorStore struct {
 	ExpiresAt    int64             `json:"expires_at"`
 	LastActiveAt int64             `json:"last_active_at"`
 	Metadata     map[string]string `json:"metadata,omitempty"`
+
+	// Fields returned by the API which the SDK does not model yet.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+
+	rawJSON json.RawMessage
+}
+
+// RawJSON returns the JSON the VectorStore was decoded from.
+func (v *VectorStore) RawJSON() json.RawMessage {
+	return v.rawJSON
+}
+
+func (v *VectorStore) UnmarshalJSON(data []byte) error {
+	type alias VectorStore
+	extra, err := unmarshalWithExtra(data, (*alias)(v))
+	v.ExtraFields = extra
+	v.rawJSON = append(json.RawMessage(nil), data...)
+	return err
 }
 
+func (v VectorStore) MarshalJSON() ([]byte, error) {
+	type alias VectorStore
+	return marshalWithExtra(alias(v), v.ExtraFields)
+}
+
+func (v *VectorStore) objectID() string { return v.Id }
+
 type VectorStores struct {
 	Object string        `json:"object"`
 	Data   []VectorStore `json:"data"`
 }
 
 // Returns a list of vector stores.
-func (e *VectorStoresEndpoint) ListVectorStores() ([]VectorStore, error) {
-	var vectorStores VectorStores
-	err := e.do(e, "GET", "", nil, nil, &vectorStores)
-	if err == nil && vectorStores.Object != "list" {
-		err = fmt.Errorf("expected 'list' object type, got %s", vectorStores.Object)
-	}
-	return vectorStores.Data, err
+func (e *VectorStoresEndpoint) ListVectorStores(opts ...option.RequestOption) ([]VectorStore, error) {
+	return e.ListVectorStoresWithContext(context.Background(), opts...)
+}
+
+// ListVectorStoresWithContext is like ListVectorStores but uses ctx for the request.
+func (e *VectorStoresEndpoint) ListVectorStoresWithContext(ctx context.Context, opts ...option.RequestOption) ([]VectorStore, error) {
+	page, err := e.ListVectorStoresPage(ctx, nil, opts...)
+	return page.Data, err
+}
+
+// ListVectorStoresPage returns a page of vector stores.
+func (e *VectorStoresEndpoint) ListVectorStoresPage(ctx context.Context, params *ListParams, opts ...option.RequestOption) (*Page[VectorStore], error) {
+	return listPage[VectorStore](ctx, e.Client, e, "", params, opts...)
+}
+
+// ListVectorStoresAutoPaging iterates over every vector store, starting from params.
+func (e *VectorStoresEndpoint) ListVectorStoresAutoPaging(ctx context.Context, params *ListParams, opts ...option.RequestOption) *Iter[VectorStore] {
+	return newIter(ctx, params, e.ListVectorStoresPage, opts...)
 }
 
 type CreateVectorStoresRequest struct {
@@ -65,19 +104,37 @@ type CreateVectorStoresRequest struct {
 	Name         string            `json:"name"`
 	ExpiresAfter *ExpiresAfter     `json:"expires_after,omitempty"`
 	MetaData     map[string]string `json:"metadata,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (c CreateVectorStoresRequest) MarshalJSON() ([]byte, error) {
+	type alias CreateVectorStoresRequest
+	return marshalWithExtra(alias(c), c.ExtraFields)
 }
 
 // Create a vector store.
-func (e *VectorStoresEndpoint) CreateVectorStore(req *CreateVectorStoresRequest) (*VectorStore, error) {
+func (e *VectorStoresEndpoint) CreateVectorStore(req *CreateVectorStoresRequest, opts ...option.RequestOption) (*VectorStore, error) {
+	return e.CreateVectorStoreWithContext(context.Background(), req, opts...)
+}
+
+// CreateVectorStoreWithContext is like CreateVectorStore but uses ctx for the request.
+func (e *VectorStoresEndpoint) CreateVectorStoreWithContext(ctx context.Context, req *CreateVectorStoresRequest, opts ...option.RequestOption) (*VectorStore, error) {
 	var vectorStore VectorStore
-	err := e.do(e, "POST", "", req, nil, &vectorStore)
+	err := e.do(ctx, e, "POST", "", req, nil, &vectorStore, opts...)
 	return &vectorStore, err
 }
 
 // Retrieves a vector store.
-func (e *VectorStoresEndpoint) RetrieveVectorStore(vectorStoreId string) (*VectorStore, error) {
+func (e *VectorStoresEndpoint) RetrieveVectorStore(vectorStoreId string, opts ...option.RequestOption) (*VectorStore, error) {
+	return e.RetrieveVectorStoreWithContext(context.Background(), vectorStoreId, opts...)
+}
+
+// RetrieveVectorStoreWithContext is like RetrieveVectorStore but uses ctx for the request.
+func (e *VectorStoresEndpoint) RetrieveVectorStoreWithContext(ctx context.Context, vectorStoreId string, opts ...option.RequestOption) (*VectorStore, error) {
 	var vectorStore VectorStore
-	err := e.do(e, "GET", vectorStoreId, nil, nil, &vectorStore)
+	err := e.do(ctx, e, "GET", vectorStoreId, nil, nil, &vectorStore, opts...)
 	return &vectorStore, err
 }
 
@@ -85,12 +142,25 @@ type ModifyVectorStoresRequest struct {
 	Name         string            `json:"name"`
 	ExpiresAfter ExpiresAfter      `json:"expires_after"`
 	MetaData     map[string]string `json:"metadata,omitempty"`
+
+	// Additional fields merged into the request body, e.g. parameters the SDK does not model yet.
+	ExtraFields map[string]any `json:"-"`
+}
+
+func (m ModifyVectorStoresRequest) MarshalJSON() ([]byte, error) {
+	type alias ModifyVectorStoresRequest
+	return marshalWithExtra(alias(m), m.ExtraFields)
 }
 
 // Modifies a vector store.
-func (e *VectorStoresEndpoint) ModifyVectorStore(vectorStoreId string, req *ModifyVectorStoresRequest) (*VectorStore, error) {
+func (e *VectorStoresEndpoint) ModifyVectorStore(vectorStoreId string, req *ModifyVectorStoresRequest, opts ...option.RequestOption) (*VectorStore, error) {
+	return e.ModifyVectorStoreWithContext(context.Background(), vectorStoreId, req, opts...)
+}
+
+// ModifyVectorStoreWithContext is like ModifyVectorStore but uses ctx for the request.
+func (e *VectorStoresEndpoint) ModifyVectorStoreWithContext(ctx context.Context, vectorStoreId string, req *ModifyVectorStoresRequest, opts ...option.RequestOption) (*VectorStore, error) {
 	var vectorStore VectorStore
-	err := e.do(e, "POST", vectorStoreId, req, nil, &vectorStore)
+	err := e.do(ctx, e, "POST", vectorStoreId, req, nil, &vectorStore, opts...)
 	return &vectorStore, err
 }
 
@@ -101,8 +171,13 @@ type DeletionStatus struct {
 }
 
 // Deletes a vector store.
-func (e *VectorStoresEndpoint) DeleteVectorStore(vectorStoreId string) (*DeletionStatus, error) {
+func (e *VectorStoresEndpoint) DeleteVectorStore(vectorStoreId string, opts ...option.RequestOption) (*DeletionStatus, error) {
+	return e.DeleteVectorStoreWithContext(context.Background(), vectorStoreId, opts...)
+}
+
+// DeleteVectorStoreWithContext is like DeleteVectorStore but uses ctx for the request.
+func (e *VectorStoresEndpoint) DeleteVectorStoreWithContext(ctx context.Context, vectorStoreId string, opts ...option.RequestOption) (*DeletionStatus, error) {
 	var status DeletionStatus
-	err := e.do(e, "DELETE", vectorStoreId, nil, nil, &status)
+	err := e.do(ctx, e, "DELETE", vectorStoreId, nil, nil, &status, opts...)
 	return &status, err
 }
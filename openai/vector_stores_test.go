@@ -55,7 +55,7 @@ func TestListVectorStores(t *testing.T) {
 
 func TestCreateVectorStore(t *testing.T) {
 	ts := openai_test.NewTestServer()
-	ts.RegisterHandler("/v1/vector_stores/", func(w http.ResponseWriter, _ *http.Request) {
+	ts.RegisterHandler("/v1/vector_stores", func(w http.ResponseWriter, _ *http.Request) {
 		resBytes, _ := json.Marshal(openai.VectorStore{
 			Id:         "testVectorStoreId",
 			Object:     "vector_store",